@@ -0,0 +1,136 @@
+package stream
+
+import (
+	"runtime"
+	"time"
+)
+
+// Auto Parallel(Auto) picks the number of goroutines of each evaluation from runtime.GOMAXPROCS,
+// the number of elements and the cost of an element, measured on the first elements.
+// See: AutoProfile
+const Auto = -1
+
+// AutoProfile Tunes how Parallel(Auto) picks the number of goroutines.
+//
+// The first elements of the source, at most SampleSize, are processed sequentially to measure the cost of an element.
+// If the estimated duration of the remaining elements is under SequentialCutoff then they are processed sequentially,
+// otherwise by enough goroutines for each of them to run at least GoroutineWork, at most MaxGoroutines.
+type AutoProfile struct {
+	// MaxGoroutines The maximum number of goroutines.
+	MaxGoroutines int
+	// SampleSize The maximum number of elements processed sequentially to measure the cost of an element.
+	SampleSize int
+	// SequentialCutoff The estimated duration under which the evaluation stays sequential,
+	// the sampling also stops once it has taken this duration.
+	SequentialCutoff time.Duration
+	// GoroutineWork The minimum estimated duration of the work of each goroutine, so their overhead is amortized.
+	GoroutineWork time.Duration
+}
+
+// CPUBound Returns the profile of the stages doing CPU operations, the default profile of Parallel(Auto).
+// The goroutines are limited to runtime.GOMAXPROCS.
+func CPUBound() AutoProfile {
+	return AutoProfile{
+		MaxGoroutines:    runtime.GOMAXPROCS(0),
+		SampleSize:       16,
+		SequentialCutoff: 100 * time.Microsecond,
+		GoroutineWork:    50 * time.Microsecond,
+	}
+}
+
+// IOBound Returns the profile of the stages waiting for IO operations,
+// the goroutines are not limited by the CPUs and the sampling is shorter.
+func IOBound() AutoProfile {
+	return AutoProfile{
+		MaxGoroutines:    64 * runtime.GOMAXPROCS(0),
+		SampleSize:       4,
+		SequentialCutoff: 100 * time.Microsecond,
+		GoroutineWork:    10 * time.Microsecond,
+	}
+}
+
+// WithProfile Sets the profile of Parallel(Auto), CPUBound by default.
+func (stream SliceStream[E]) WithProfile(profile AutoProfile) SliceStream[E] {
+	stream.profile = &profile
+	return stream
+}
+
+// autoProfile Returns the profile of Parallel(Auto) of the pipeline.
+func (pipe *Pipeline[E]) autoProfile() AutoProfile {
+	if pipe.profile != nil {
+		return *pipe.profile
+	}
+	return CPUBound()
+}
+
+// parallelism Returns the maximum number of goroutines of the evaluations of the pipeline, 1 if sequential.
+func (pipe *Pipeline[E]) parallelism() int {
+	if pipe.goroutines == Auto {
+		return pipe.autoProfile().MaxGoroutines
+	}
+	if pipe.goroutines < 1 {
+		return 1
+	}
+	return pipe.goroutines
+}
+
+// goroutines Returns the number of goroutines for the remaining elements, remaining < 0 if unknown,
+// from the duration of the sampled elements.
+func (profile AutoProfile) goroutines(sampled int, elapsed time.Duration, remaining int) int {
+	if sampled == 0 || remaining == 0 {
+		return 1
+	}
+	if remaining < 0 {
+		// The elements of an unknown source are processed by batches, at least one is estimated.
+		remaining = defaultBatchSize * profile.MaxGoroutines
+	}
+	total := elapsed / time.Duration(sampled) * time.Duration(remaining)
+	if total < profile.SequentialCutoff {
+		return 1
+	}
+	n := profile.MaxGoroutines
+	if profile.GoroutineWork > 0 && total/profile.GoroutineWork < time.Duration(n) {
+		n = int(total / profile.GoroutineWork)
+	}
+	if n > remaining {
+		n = remaining
+	}
+	if n < 2 {
+		return 1
+	}
+	return n
+}
+
+// autoSample Processes the first elements of the source sequentially to measure their cost,
+// returns the number of goroutines for the remaining elements and the index of the first of them.
+// more is false if the source is exhausted or the evaluation completed.
+func autoSample[E any, R any](pipe *Pipeline[E], handler flow[E, R], emit func(R) bool) (goroutines int, start int, more bool) {
+	profile := pipe.autoProfile()
+	begin := time.Now()
+	var elapsed time.Duration
+	for start < profile.SampleSize && elapsed < profile.SequentialCutoff {
+		var e E
+		if pipe.gen != nil {
+			var ok bool
+			if e, ok = pipe.gen.next(); !ok {
+				return 1, start, false
+			}
+		} else {
+			if start >= len(pipe.source) {
+				return 1, start, false
+			}
+			e = pipe.source[start]
+		}
+		start++
+		if !handler(start-1, e, emit) {
+			return 1, start, false
+		}
+		elapsed = time.Since(begin)
+	}
+
+	remaining := pipe.size()
+	if remaining >= 0 {
+		remaining -= start
+	}
+	return profile.goroutines(start, elapsed, remaining), start, true
+}
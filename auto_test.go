@@ -0,0 +1,100 @@
+package stream
+
+import (
+	"runtime"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAutoProfileGoroutines(t *testing.T) {
+	profile := AutoProfile{MaxGoroutines: 8, SampleSize: 16, SequentialCutoff: 100 * time.Microsecond, GoroutineWork: 50 * time.Microsecond}
+
+	// Cheap elements stay sequential.
+	assert.Equal(t, 1, profile.goroutines(16, time.Microsecond, 1000))
+	// Nothing remains.
+	assert.Equal(t, 1, profile.goroutines(16, time.Millisecond, 0))
+	assert.Equal(t, 1, profile.goroutines(0, 0, 1000))
+	// 10µs per element, 1ms in total, 20 goroutines are needed but 8 at most.
+	assert.Equal(t, 8, profile.goroutines(10, 100*time.Microsecond, 100))
+	// 10µs per element, 200µs in total, 4 goroutines of 50µs.
+	assert.Equal(t, 4, profile.goroutines(10, 100*time.Microsecond, 20))
+	// At most one goroutine per element.
+	assert.Equal(t, 3, profile.goroutines(1, time.Millisecond, 3))
+	// Unknown number of elements.
+	assert.Equal(t, 8, profile.goroutines(16, 16*time.Microsecond, -1))
+
+	assert.Equal(t, runtime.GOMAXPROCS(0), CPUBound().MaxGoroutines)
+	assert.Greater(t, IOBound().MaxGoroutines, CPUBound().MaxGoroutines)
+}
+
+func TestParallelAuto(t *testing.T) {
+	var executed int64
+	counting := ExecutorFunc(func(task func()) {
+		atomic.AddInt64(&executed, 1)
+		go task()
+	})
+	slow := func(v int) int {
+		time.Sleep(100 * time.Microsecond)
+		return v * 2
+	}
+	expected := make([]int, 0, 200)
+	source := make([]int, 0, 200)
+	for i := 0; i < 200; i++ {
+		source = append(source, i)
+		expected = append(expected, i*2)
+	}
+
+	// Cheap elements are evaluated sequentially, the generous cutoff keeps the test stable on slow machines.
+	cheap := AutoProfile{MaxGoroutines: 8, SampleSize: 16, SequentialCutoff: time.Minute, GoroutineWork: time.Second}
+	got := NewSlice(source).WithExecutor(counting).WithProfile(cheap).Parallel(Auto).Map(func(v int) int { return v * 2 }).ToSlice()
+	assert.Equal(t, expected, got)
+	assert.Equal(t, int64(0), atomic.LoadInt64(&executed))
+
+	// Slow elements are evaluated by goroutines after the sampling, in order.
+	got = NewSlice(source).WithExecutor(counting).WithProfile(IOBound()).Parallel(Auto).Map(slow).ToSlice()
+	assert.Equal(t, expected, got)
+	assert.Greater(t, atomic.LoadInt64(&executed), int64(1))
+
+	atomic.StoreInt64(&executed, 0)
+	got = NewSlice(source).WithExecutor(counting).Parallel(Auto).WithProfile(AutoProfile{
+		MaxGoroutines:    1,
+		SampleSize:       4,
+		SequentialCutoff: time.Microsecond,
+		GoroutineWork:    time.Microsecond,
+	}).Map(slow).ToSlice()
+	assert.Equal(t, expected, got)
+	assert.Equal(t, int64(0), atomic.LoadInt64(&executed))
+
+	// Generated sources, filters and short-circuiting operations.
+	got = RangeFrom(0, 1).WithProfile(IOBound()).Parallel(Auto).
+		Filter(func(v int) bool { return v%2 == 0 }).
+		Map(slow).
+		Limit(100).
+		ToSlice()
+	assert.Len(t, got, 100)
+	for i, v := range got {
+		assert.Equal(t, i*4, v)
+	}
+
+	got = FromChan(sendAll(source)).WithProfile(IOBound()).Parallel(Auto).Map(slow).ToSlice()
+	assert.Equal(t, expected, got)
+
+	// Sources shorter than the sampling.
+	assert.Equal(t, []int{2, 4}, NewSlice([]int{1, 2}).Parallel(Auto).Map(slow).ToSlice())
+	assert.Empty(t, NewSlice([]int{}).Parallel(Auto).Map(slow).ToSlice())
+	max, ok := NewSlice([]int{3, 1, 2}).Parallel(Auto).MaxFunc(func(a, b int) bool { return a > b })
+	assert.True(t, ok)
+	assert.Equal(t, 3, max)
+
+	// Stateful stages.
+	sorted := NewSliceByOrdered([]int{5, 3, 3, 1, 4, 1}).Parallel(Auto).Distinct().Sort().ToSlice()
+	assert.Equal(t, []int{1, 3, 4, 5}, sorted)
+}
+
+func TestExplainAuto(t *testing.T) {
+	plan := NewSlice([]int{1, 2, 3}).Parallel(Auto).Map(func(v int) int { return v }).Explain()
+	assert.Contains(t, plan, "Pipeline (parallel: auto)\n")
+}
@@ -1,6 +1,7 @@
 package stream
 
 import (
+	"context"
 	"fmt"
 	"sort"
 	"testing"
@@ -34,6 +35,38 @@ func BenchmarkParallelByCPU(b *testing.B) {
 	}
 }
 
+func BenchmarkParallelAfterStatefulStage(b *testing.B) {
+	tests := []struct {
+		name       string
+		goroutines int
+	}{
+		{name: "no Parallel", goroutines: 0},
+		{name: "Goroutines", goroutines: 4},
+		{name: "Goroutines", goroutines: 8},
+	}
+	s := newArray(100)
+
+	for _, tt := range tests {
+		b.Run(fmt.Sprintf("Sort %s(%d)", tt.name, tt.goroutines), func(b *testing.B) {
+			b.ResetTimer()
+			for n := 0; n < b.N; n++ {
+				NewSliceByOrdered(s).Parallel(tt.goroutines).Sort().ForEach(func(i int, v int) {
+					sort.Ints(newArray(1000)) // Simulate time-consuming CPU operations
+				})
+			}
+		})
+		b.Run(fmt.Sprintf("Limit %s(%d)", tt.name, tt.goroutines), func(b *testing.B) {
+			b.ResetTimer()
+			for n := 0; n < b.N; n++ {
+				NewSlice(s).Parallel(tt.goroutines).Limit(len(s)).Map(func(v int) int {
+					sort.Ints(newArray(1000)) // Simulate time-consuming CPU operations
+					return v
+				}).ToSlice()
+			}
+		})
+	}
+}
+
 func BenchmarkParallelByIO(b *testing.B) {
 	tests := []struct {
 		name       string
@@ -62,3 +95,28 @@ func BenchmarkParallelByIO(b *testing.B) {
 		})
 	}
 }
+
+func BenchmarkParallelByExecutor(b *testing.B) {
+	pool := NewPool(0)
+	defer pool.Shutdown(context.Background())
+	tests := []struct {
+		name     string
+		executor Executor
+	}{
+		{name: "goroutine per task", executor: ExecutorFunc(func(task func()) { go task() })},
+		{name: "pool", executor: pool},
+		{name: "default pool", executor: nil},
+	}
+	s := newArray(100)
+
+	for _, tt := range tests {
+		b.Run(tt.name, func(b *testing.B) {
+			b.ResetTimer()
+			for n := 0; n < b.N; n++ {
+				NewSlice(s).WithExecutor(tt.executor).Parallel(4).Map(func(v int) int {
+					return v * 2 // Simulate the small streams of request handlers
+				}).ToSlice()
+			}
+		})
+	}
+}
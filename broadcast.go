@@ -0,0 +1,205 @@
+package stream
+
+import (
+	"sync"
+
+	"golang.org/x/exp/slices"
+)
+
+// Collector Accumulates the elements of a stream into a result. See: SliceStream.Broadcast
+type Collector[E any] interface {
+	// Accumulate Accumulates the element, the elements are passed in their order by a single goroutine.
+	Accumulate(e E)
+	// Result Returns the result of the accumulated elements.
+	Result() any
+}
+
+// ValueCollector Collector of a result of type R, returned by Value.
+type ValueCollector[E any, R any] struct {
+	value      R
+	accumulate func(R, E) R
+}
+
+// Accumulate See: Collector.Accumulate
+func (c *ValueCollector[E, R]) Accumulate(e E) {
+	c.value = c.accumulate(c.value, e)
+}
+
+// Result See: Collector.Result
+func (c *ValueCollector[E, R]) Result() any {
+	return c.value
+}
+
+// Value Returns the result of the accumulated elements.
+func (c *ValueCollector[E, R]) Value() R {
+	return c.value
+}
+
+// Reducing Returns a collector of the reduction of the elements by the accumulator, starting from the identity.
+func Reducing[E any, R any](identity R, accumulator func(R, E) R) *ValueCollector[E, R] {
+	return &ValueCollector[E, R]{value: identity, accumulate: accumulator}
+}
+
+// Counting Returns a collector of the number of elements.
+func Counting[E any]() *ValueCollector[E, int] {
+	return Reducing(0, func(n int, _ E) int { return n + 1 })
+}
+
+// Summing Returns a collector of the sum of the numbers returned by the mapper for each element.
+func Summing[E any, N Number](mapper func(E) N) *ValueCollector[E, N] {
+	return Reducing(N(0), func(sum N, e E) N { return sum + mapper(e) })
+}
+
+// ToList Returns a collector of the elements in their order.
+func ToList[E any]() *ValueCollector[E, []E] {
+	return Reducing([]E{}, func(list []E, e E) []E { return append(list, e) })
+}
+
+// Filtering Returns a collector passing the elements that match the predicate to the downstream collector,
+// its result is the result of the downstream collector.
+func Filtering[E any](predicate func(E) bool, downstream Collector[E]) Collector[E] {
+	return &adaptedCollector[E, E]{downstream: downstream, accumulate: func(e E, next func(E)) {
+		if predicate(e) {
+			next(e)
+		}
+	}}
+}
+
+// Mapping Returns a collector passing the results of the mapper for each element to the downstream collector,
+// its result is the result of the downstream collector.
+func Mapping[E any, R any](mapper func(E) R, downstream Collector[R]) Collector[E] {
+	return &adaptedCollector[E, R]{downstream: downstream, accumulate: func(e E, next func(R)) {
+		next(mapper(e))
+	}}
+}
+
+// Consuming Returns a collector performing the action for each element, such as writing it to a sink, its result is nil.
+func Consuming[E any](action func(E)) Collector[E] {
+	return &adaptedCollector[E, E]{accumulate: func(e E, _ func(E)) { action(e) }}
+}
+
+// adaptedCollector Collector passing the elements to the downstream collector through accumulate.
+type adaptedCollector[E any, R any] struct {
+	downstream Collector[R]
+	accumulate func(e E, next func(R))
+}
+
+func (c *adaptedCollector[E, R]) Accumulate(e E) {
+	c.accumulate(e, c.next)
+}
+
+func (c *adaptedCollector[E, R]) next(r R) {
+	c.downstream.Accumulate(r)
+}
+
+func (c *adaptedCollector[E, R]) Result() any {
+	if c.downstream == nil {
+		return nil
+	}
+	return c.downstream.Result()
+}
+
+// Broadcast Evaluates the pending stages once, passing each element to every collector,
+// returns the results of the collectors in their order.
+// The typed result of a ValueCollector is also returned by its Value.
+//
+// Support Parallel, the collectors accumulate concurrently, each in its own goroutine, the elements in their order.
+func (stream SliceStream[E]) Broadcast(collectors ...Collector[E]) []any {
+	stream.record(StageInfo{Name: "Broadcast", Evaluation: true})
+	if stream.source != nil || stream.gen != nil {
+		stream.bounded()
+		if stream.parallelism() > 1 && len(collectors) > 1 {
+			broadcast(stream.Pipeline, collectors)
+		} else {
+			stream.each(func(e E) bool {
+				for _, c := range collectors {
+					c.Accumulate(e)
+				}
+				return true
+			})
+		}
+	}
+
+	results := make([]any, len(collectors))
+	for i, c := range collectors {
+		results[i] = c.Result()
+	}
+	return results
+}
+
+// broadcast Evaluates the pipeline, passing the elements by batches to a goroutine for each collector.
+func broadcast[E any](pipe *Pipeline[E], collectors []Collector[E]) {
+	chs := make([]chan []E, len(collectors))
+	panics := make(chan any, len(collectors))
+	var wg sync.WaitGroup
+	for i, c := range collectors {
+		c, ch := c, make(chan []E, len(collectors))
+		chs[i] = ch
+		wg.Add(1)
+		// The collectors wait for the batches, so they run in their own goroutines
+		// rather than by the executor, which may run a task in the evaluating goroutine.
+		go func() {
+			defer wg.Done()
+			defer func() {
+				if r := recover(); r != nil {
+					panics <- r
+					for range ch {
+					}
+				}
+			}()
+			for batch := range ch {
+				for _, e := range batch {
+					c.Accumulate(e)
+				}
+			}
+		}()
+	}
+
+	batch := make([]E, 0, chunkSize)
+	send := func() {
+		// The batch is only read by the collectors, so it is shared by all of them.
+		for _, ch := range chs {
+			ch <- batch
+		}
+		batch = make([]E, 0, chunkSize)
+	}
+	func() {
+		defer func() {
+			if len(batch) > 0 {
+				send()
+			}
+			for _, ch := range chs {
+				close(ch)
+			}
+			wg.Wait()
+		}()
+		pipe.each(func(e E) bool {
+			batch = append(batch, e)
+			if len(batch) == chunkSize {
+				send()
+			}
+			return true
+		})
+	}()
+
+	select {
+	case r := <-panics:
+		panic(r)
+	default:
+	}
+}
+
+// Tee Evaluates the pending stages once, returns n streams of the elements.
+// Each stream has its own copy of the elements and inherits the plan and the options of this stream,
+// so each of them can be used by its own chain of operations.
+func (stream SliceStream[E]) Tee(n int) []SliceStream[E] {
+	stream.evaluationBy(StageInfo{Name: "Tee", Stateful: true})
+	streams := make([]SliceStream[E], n)
+	for i := range streams {
+		dst := &Pipeline[E]{source: slices.Clone(stream.source)}
+		inherit(dst, stream.Pipeline)
+		dst.partitioner = stream.partitioner
+		streams[i] = SliceStream[E]{Pipeline: dst}
+	}
+	return streams
+}
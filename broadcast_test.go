@@ -0,0 +1,79 @@
+package stream
+
+import (
+	"strconv"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBroadcast(t *testing.T) {
+	for _, goroutines := range []int{0, 4} {
+		var calls int32
+		count := Counting[int]()
+		sum := Summing(func(v int) int { return v })
+		evens := ToList[int]()
+		var sunk []string
+		results := Range(1, 1001, 1).
+			Parallel(goroutines).
+			Map(func(v int) int {
+				atomic.AddInt32(&calls, 1)
+				return v
+			}).
+			Broadcast(
+				count,
+				sum,
+				Filtering[int](func(v int) bool { return v%2 == 0 }, evens),
+				Mapping[int, string](strconv.Itoa, Reducing("", func(r string, s string) string { return r + s })),
+				Consuming(func(v int) {
+					if v <= 3 {
+						sunk = append(sunk, strconv.Itoa(v))
+					}
+				}),
+			)
+
+		assert.Equal(t, int32(1000), calls)
+		assert.Len(t, results, 5)
+		assert.Equal(t, 1000, results[0])
+		assert.Equal(t, 1000, count.Value())
+		assert.Equal(t, 500500, sum.Value())
+		assert.Len(t, evens.Value(), 500)
+		assert.Equal(t, evens.Value(), results[2])
+		for i, v := range evens.Value() {
+			assert.Equal(t, (i+1)*2, v)
+		}
+		assert.Equal(t, "123456789101112", results[3].(string)[:15])
+		assert.Nil(t, results[4])
+		assert.Equal(t, []string{"1", "2", "3"}, sunk)
+	}
+
+	assert.Equal(t, []any{0, []int{}}, NewSlice[int](nil).Broadcast(Counting[int](), ToList[int]()))
+	assert.Empty(t, NewSlice([]int{1}).Broadcast())
+
+	assert.Panics(t, func() {
+		NewSlice([]int{1, 2, 3}).Parallel(2).Broadcast(Counting[int](), Consuming(func(v int) {
+			if v == 2 {
+				panic("boom")
+			}
+		}))
+	})
+}
+
+func TestTee(t *testing.T) {
+	var calls int32
+	streams := NewSlice([]int{3, 1, 2}).Parallel(2).Map(func(v int) int {
+		atomic.AddInt32(&calls, 1)
+		return v * 10
+	}).Tee(2)
+	assert.Len(t, streams, 2)
+
+	sorted := NewSliceByOrdered(streams[0].ToSlice()).Sort().ToSlice()
+	filtered := streams[1].Filter(func(v int) bool { return v > 10 }).ToSlice()
+	assert.Equal(t, []int{10, 20, 30}, sorted)
+	assert.Equal(t, []int{30, 20}, filtered)
+	assert.Equal(t, int32(3), calls)
+
+	assert.Contains(t, streams[1].Explain(), "Tee [evaluation, stateful]")
+	assert.Empty(t, NewSlice([]int{1}).Tee(0))
+}
@@ -0,0 +1,167 @@
+package stream
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"os"
+	"sync"
+
+	"golang.org/x/exp/slices"
+)
+
+// Cached Elements of a stream evaluated once, reused by the streams returned by Stream. See: SliceStream.Cache
+type Cached[E any] struct {
+	mu sync.Mutex
+	// base The plan and the options inherited by the returned streams.
+	base *Pipeline[E]
+	// source The elements kept in memory.
+	source []E
+	// path The file of the elements persisted to disk, empty if they are kept in memory.
+	path  string
+	codec Codec[E]
+	size  int
+	err   error
+	// released Whether Unpersist was called.
+	released bool
+}
+
+// Cache Evaluates the pending stages once and keeps the elements in memory,
+// so several streams can be derived from them without evaluating the stages again. See: Cached.Stream
+//
+// The operations of a stream modify it, so a stream can only be used by one chain of operations,
+// the cached elements are a copy that the stream and the derived streams cannot modify.
+// An error of the evaluation is reported by Cached.Err and by the derived streams, with the elements evaluated before it.
+func (stream SliceStream[E]) Cache() *Cached[E] {
+	stream.evaluationBy(StageInfo{Name: "Cache", Stateful: true})
+	return &Cached[E]{base: stream.Pipeline, source: slices.Clone(stream.source), size: len(stream.source), err: stream.err}
+}
+
+// Persist Evaluates the pending stages once and writes the elements to a temporary file of dir encoded by the codec,
+// for the results that do not fit in memory. The elements are written as they are evaluated,
+// and read again lazily by each stream derived from them. See: Cached.Stream
+//
+// If codec is nil then GobCodec is used, if dir is empty then os.TempDir is used.
+// The file is removed by Unpersist. An error of the file is reported by Cached.Err and by the derived streams.
+func (stream SliceStream[E]) Persist(codec Codec[E], dir string) *Cached[E] {
+	stream.record(StageInfo{Name: "Persist", Stateful: true, Evaluation: true})
+	if codec == nil {
+		codec = GobCodec[E]()
+	}
+	c := &Cached[E]{base: stream.Pipeline, codec: codec}
+	if stream.source == nil && stream.gen == nil {
+		return c
+	}
+	stream.bounded()
+
+	f, err := os.CreateTemp(dir, "stream-persist-*")
+	if err != nil {
+		c.err = err
+		return c
+	}
+	c.path = f.Name()
+	w := bufio.NewWriter(f)
+	enc := codec.NewEncoder(w)
+	stream.each(func(e E) bool {
+		if c.err = enc.Encode(e); c.err != nil {
+			return false
+		}
+		c.size++
+		return true
+	})
+	if c.err == nil {
+		c.err = w.Flush()
+	}
+	if err := f.Close(); c.err == nil {
+		c.err = err
+	}
+	if c.err == nil {
+		c.err = stream.err
+	}
+	return c
+}
+
+// Stream Returns a new stream of the cached elements, inheriting the plan and the options of the cached stream.
+// Each stream has its own copy of the elements, or reads its own file handle, so the streams cannot modify the cache.
+// Panics if the cache was unpersisted.
+func (c *Cached[E]) Stream() SliceStream[E] {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.released {
+		panic("stream: the cached stream was unpersisted")
+	}
+
+	dst := &Pipeline[E]{}
+	inherit(dst, c.base)
+	dst.partitioner = c.base.partitioner
+	dst.err = c.err
+	switch {
+	case c.path != "" && c.err == nil:
+		dst.gen = c.read()
+	case c.source != nil:
+		dst.source = slices.Clone(c.source)
+	case c.err != nil:
+		dst.source = []E{}
+	}
+	return SliceStream[E]{Pipeline: dst}
+}
+
+// read new generator of the elements of the file, opened when the first element is pulled.
+func (c *Cached[E]) read() *generator[E] {
+	var f *os.File
+	var dec Decoder[E]
+	gen := pulled[E](false, nil)
+	gen.size = c.size
+	gen.next = func() (e E, ok bool) {
+		if f == nil {
+			if f, gen.err = os.Open(c.path); gen.err != nil {
+				return
+			}
+			dec = c.codec.NewDecoder(bufio.NewReader(f))
+		}
+		e, err := dec.Decode()
+		if errors.Is(err, io.EOF) {
+			return
+		}
+		if err != nil {
+			gen.err = err
+			return
+		}
+		return e, true
+	}
+	gen.close = func() {
+		if f != nil {
+			_ = f.Close()
+		}
+	}
+	return gen
+}
+
+// Len Returns the number of cached elements.
+func (c *Cached[E]) Len() int {
+	return c.size
+}
+
+// Err Returns the error of the evaluation or of the file of the cached elements, if any.
+func (c *Cached[E]) Err() error {
+	return c.err
+}
+
+// Unpersist Releases the cached elements, removing the file of Persist. The cache can no longer be streamed.
+func (c *Cached[E]) Unpersist() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.released {
+		return nil
+	}
+	c.released = true
+	c.source = nil
+	if c.path == "" {
+		return nil
+	}
+	err := os.Remove(c.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,101 @@
+package stream
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCache(t *testing.T) {
+	var calls int32
+	expensive := func(v int) int {
+		atomic.AddInt32(&calls, 1)
+		return v * 10
+	}
+	cached := NewSlice([]int{1, 2, 3, 4, 5}).Parallel(2).Map(expensive).Cache()
+	assert.Equal(t, int32(5), calls)
+	assert.Equal(t, 5, cached.Len())
+	assert.NoError(t, cached.Err())
+
+	evens := cached.Stream().Filter(func(v int) bool { return v%20 == 0 }).ToSlice()
+	odds := cached.Stream().Filter(func(v int) bool { return v%20 != 0 }).ToSlice()
+	assert.Equal(t, []int{20, 40}, evens)
+	assert.Equal(t, []int{10, 30, 50}, odds)
+	assert.Equal(t, 150, cached.Stream().Reduce(0, func(r, e int) int { return r + e }))
+	assert.Equal(t, int32(5), calls)
+
+	// The derived streams cannot modify the cache.
+	cached.Stream().Delete(0, 2).Shuffle(nil)
+	assert.Equal(t, []int{10, 20, 30, 40, 50}, cached.Stream().ToSlice())
+
+	// The derived streams inherit the plan and the options.
+	plan := cached.Stream().Map(func(v int) int { return v }).Explain()
+	assert.Contains(t, plan, "Pipeline (parallel: 2 goroutines)")
+	assert.Contains(t, plan, "Cache [evaluation, stateful]")
+
+	assert.NoError(t, cached.Unpersist())
+	assert.NoError(t, cached.Unpersist())
+	assert.Panics(t, func() { cached.Stream() })
+
+	assert.Empty(t, NewSlice[int](nil).Cache().Stream().ToSlice())
+	assert.Equal(t, []int{}, NewSlice([]int{}).Cache().Stream().ToSlice())
+
+	// The elements evaluated before an error are cached with it.
+	errRead := errors.New("read")
+	lines := FromReader(&failingReader{r: strings.NewReader("a\nb\n"), err: errRead}, nil).Cache()
+	assert.ErrorIs(t, lines.Err(), errRead)
+	got, err := lines.Stream().ToSliceErr()
+	assert.Equal(t, []string{"a", "b"}, got)
+	assert.ErrorIs(t, err, errRead)
+}
+
+func TestPersist(t *testing.T) {
+	dir := t.TempDir()
+	var calls int32
+	persisted := Range(0, 1000, 1).Parallel(4).Map(func(v int) int {
+		atomic.AddInt32(&calls, 1)
+		return v * 2
+	}).Persist(nil, dir)
+	assert.NoError(t, persisted.Err())
+	assert.Equal(t, 1000, persisted.Len())
+	entries, _ := os.ReadDir(dir)
+	assert.Len(t, entries, 1)
+
+	for i := 0; i < 2; i++ {
+		sum := persisted.Stream().Parallel(3).Filter(func(v int) bool { return v%4 == 0 }).Reduce(0, func(r, e int) int { return r + e })
+		assert.Equal(t, 2*(0+998)*500/2, sum)
+	}
+	first := persisted.Stream().Limit(3).ToSlice()
+	assert.Equal(t, []int{0, 2, 4}, first)
+	assert.Equal(t, int32(1000), calls)
+
+	assert.NoError(t, persisted.Unpersist())
+	assertEmptyDir(t, dir)
+	assert.Panics(t, func() { persisted.Stream() })
+
+	// A custom codec and an empty stream.
+	words := NewSlice([]string{"b", "a"}).Persist(jsonCodec[string]{}, dir)
+	assert.Equal(t, []string{"b", "a"}, words.Stream().ToSlice())
+	assert.NoError(t, words.Unpersist())
+	empty := NewSlice([]string{}).Persist(nil, dir)
+	assert.Empty(t, empty.Stream().ToSlice())
+	assert.NoError(t, empty.Unpersist())
+	assert.Empty(t, NewSlice[string](nil).Persist(nil, dir).Stream().ToSlice())
+
+	// Errors of the file.
+	failing := NewSlice([]int{1, 2}).Persist(failingCodec[int]{}, dir)
+	assert.Error(t, failing.Err())
+	got, err := failing.Stream().ToSliceErr()
+	assert.Empty(t, got)
+	assert.Error(t, err)
+	assert.NoError(t, failing.Unpersist())
+
+	missing := NewSlice([]int{1, 2}).Persist(nil, dir+"/missing")
+	assert.Error(t, missing.Err())
+	assert.NoError(t, missing.Unpersist())
+	assertEmptyDir(t, dir)
+}
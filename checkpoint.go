@@ -0,0 +1,365 @@
+package stream
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ErrCheckpointMismatch The checkpoint was saved by the evaluation of a source of another size.
+var ErrCheckpointMismatch = errors.New("stream: checkpoint of a source of another size")
+
+// IndexRange The indexes from Low (inclusive) to High (exclusive).
+type IndexRange struct {
+	Low  int
+	High int
+}
+
+// Checkpoint Progress of the evaluation of a stream, See: SliceStream.Checkpoint
+type Checkpoint struct {
+	// Size The number of elements of the source, -1 if unknown.
+	Size int
+	// Completed The ranges of the indexes of the processed elements, sorted and disjoint.
+	// Each partition of Parallel in progress ends a range at its last completed index.
+	Completed []IndexRange
+	// State The user state saved with the progress, See: CheckpointState
+	State []byte
+	// Finished Whether the evaluation has ended, the next evaluation ignores the checkpoint and starts from the first element.
+	Finished bool
+}
+
+// CheckpointStore Persists the checkpoints of an evaluation.
+// Save may be called by the goroutines of Parallel, one at a time.
+type CheckpointStore interface {
+	// Save Replaces the saved checkpoint.
+	Save(cp Checkpoint) error
+	// Load Returns the saved checkpoint, ok is false if there is none.
+	Load() (cp Checkpoint, ok bool, err error)
+}
+
+// FileCheckpointStore CheckpointStore saving the checkpoint as JSON to a file.
+// The file is replaced atomically, so a crash while saving keeps the previous checkpoint.
+type FileCheckpointStore struct {
+	path string
+}
+
+// NewFileCheckpointStore new checkpoint store saving to the file at path.
+func NewFileCheckpointStore(path string) *FileCheckpointStore {
+	return &FileCheckpointStore{path: path}
+}
+
+// Save See: CheckpointStore.Save
+func (s *FileCheckpointStore) Save(cp Checkpoint) error {
+	data, err := json.Marshal(cp)
+	if err != nil {
+		return err
+	}
+	f, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	if _, err = f.Write(data); err == nil {
+		err = f.Sync()
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(f.Name(), s.path)
+	}
+	if err != nil {
+		_ = os.Remove(f.Name())
+	}
+	return err
+}
+
+// Load See: CheckpointStore.Load
+func (s *FileCheckpointStore) Load() (cp Checkpoint, ok bool, err error) {
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return cp, false, nil
+	}
+	if err != nil {
+		return cp, false, err
+	}
+	if err = json.Unmarshal(data, &cp); err != nil {
+		return cp, false, err
+	}
+	return cp, true, nil
+}
+
+// Remove Removes the saved checkpoint, so the next evaluation starts from the first element.
+func (s *FileCheckpointStore) Remove() error {
+	err := os.Remove(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+// CheckpointOption Configures the checkpoints of SliceStream.Checkpoint.
+type CheckpointOption func(*checkpointOptions)
+
+type checkpointOptions struct {
+	every    int
+	interval time.Duration
+	save     func() ([]byte, error)
+	restore  func(state []byte) error
+}
+
+// CheckpointEvery Saves a checkpoint every n processed elements, disabled by default.
+func CheckpointEvery(n int) CheckpointOption {
+	return func(o *checkpointOptions) {
+		o.every = n
+	}
+}
+
+// CheckpointInterval Saves a checkpoint at most every interval, 1 second by default, 0 disables it.
+// The interval is checked as the elements are processed.
+func CheckpointInterval(interval time.Duration) CheckpointOption {
+	return func(o *checkpointOptions) {
+		o.interval = interval
+	}
+}
+
+// CheckpointState Saves a user state with each checkpoint, returned by save,
+// and restores it by restore before an evaluation resumed from a checkpoint.
+// With Parallel, save is called while other elements are processed, it must be safe for concurrent use.
+func CheckpointState(save func() ([]byte, error), restore func(state []byte) error) CheckpointOption {
+	return func(o *checkpointOptions) {
+		o.save = save
+		o.restore = restore
+	}
+}
+
+// checkpointConfig The checkpointing of the next evaluation of a pipeline.
+type checkpointConfig struct {
+	store CheckpointStore
+	o     checkpointOptions
+}
+
+// Checkpoint Saves the progress of the next evaluation to the store, at the intervals set by the options and when it ends.
+// If the store has a checkpoint then the evaluation resumes from it: the processed elements are skipped,
+// they are not passed to the stages, and the user state is restored. See: CheckpointState
+//
+// An element is processed once the stages before the first stateful stage have processed it,
+// so it is meant for stateless stages such as ForEach, a stateful stage such as Sort only sees the elements not skipped.
+// The elements processed since the last checkpoint are processed again after a crash.
+// Once the evaluation has ended without a panic or an error of the source, the checkpoint is saved as Finished,
+// so the next evaluation on the same store processes all the elements again.
+// The stream must be built on the same source, a source of another size stops the evaluation with ErrCheckpointMismatch,
+// the errors of the store are reported by Pipeline.Err.
+//
+// Support Parallel, each goroutine records the progress of its partition.
+func (stream SliceStream[E]) Checkpoint(store CheckpointStore, opts ...CheckpointOption) SliceStream[E] {
+	o := checkpointOptions{interval: time.Second}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	stream.checkpoint = &checkpointConfig{store: store, o: o}
+	return stream
+}
+
+// checkpointer Records the processed elements of an evaluation and saves the checkpoints.
+// Each partition of Parallel records its processed elements with its own tracker, the trackers are merged on save.
+type checkpointer struct {
+	config checkpointConfig
+	size   int
+	// resumed The ranges completed by the resumed checkpoint, sorted.
+	resumed []IndexRange
+
+	// marked The number of elements processed since the last checkpoint.
+	marked int64
+	// last The time of the last checkpoint, in nanoseconds.
+	last int64
+
+	mu       sync.Mutex
+	trackers []*checkpointTracker
+	free     []*checkpointTracker
+	seq      int
+
+	saveMu sync.Mutex
+	saved  int
+	err    error
+}
+
+// checkpointTracker The ranges of the elements processed by a partition.
+type checkpointTracker struct {
+	mu     sync.Mutex
+	byLow  map[int]*IndexRange
+	byHigh map[int]*IndexRange
+}
+
+// newCheckpointer Loads the checkpoint of the store and restores the user state.
+// A finished checkpoint is ignored, the evaluation starts from the first element.
+func newCheckpointer(config checkpointConfig, size int) (*checkpointer, error) {
+	c := &checkpointer{
+		config: config,
+		size:   size,
+		last:   time.Now().UnixNano(),
+	}
+	cp, ok, err := config.store.Load()
+	if err != nil || !ok || cp.Finished {
+		return c, err
+	}
+	if cp.Size >= 0 && size >= 0 && cp.Size != size {
+		return c, ErrCheckpointMismatch
+	}
+	if config.o.restore != nil {
+		if err = config.o.restore(cp.State); err != nil {
+			return c, err
+		}
+	}
+	c.resumed = append([]IndexRange(nil), cp.Completed...)
+	sort.Slice(c.resumed, func(i, j int) bool { return c.resumed[i].Low < c.resumed[j].Low })
+	return c, nil
+}
+
+// skipped Returns whether the element at index was processed before the checkpoint was resumed.
+func (c *checkpointer) skipped(index int) bool {
+	i := sort.Search(len(c.resumed), func(i int) bool { return c.resumed[i].High > index })
+	return i < len(c.resumed) && c.resumed[i].Low <= index
+}
+
+// acquire Returns a tracker not used by another partition.
+func (c *checkpointer) acquire() *checkpointTracker {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if n := len(c.free); n > 0 {
+		t := c.free[n-1]
+		c.free = c.free[:n-1]
+		return t
+	}
+	t := &checkpointTracker{byLow: map[int]*IndexRange{}, byHigh: map[int]*IndexRange{}}
+	c.trackers = append(c.trackers, t)
+	return t
+}
+
+// release Returns the tracker of a partition that has ended, its ranges are kept.
+func (c *checkpointer) release(t *checkpointTracker) {
+	c.mu.Lock()
+	c.free = append(c.free, t)
+	c.mu.Unlock()
+}
+
+// mark Records the element at index as processed by the tracker, and saves a checkpoint if one is due.
+func (c *checkpointer) mark(t *checkpointTracker, index int) {
+	t.add(index)
+
+	o := c.config.o
+	marked := atomic.AddInt64(&c.marked, 1)
+	now := time.Now().UnixNano()
+	due := o.every > 0 && marked >= int64(o.every)
+	if !due && o.interval > 0 && now-atomic.LoadInt64(&c.last) >= int64(o.interval) {
+		due = true
+	}
+	// Only the goroutine resetting the count saves the checkpoint.
+	if !due || !atomic.CompareAndSwapInt64(&c.marked, marked, 0) {
+		return
+	}
+	atomic.StoreInt64(&c.last, now)
+	seq, completed := c.snapshot()
+	c.save(seq, completed, false)
+}
+
+// add Records the element at index as processed.
+func (t *checkpointTracker) add(index int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	left, right := t.byHigh[index], t.byLow[index+1]
+	switch {
+	case left != nil && right != nil:
+		delete(t.byHigh, left.High)
+		delete(t.byLow, right.Low)
+		left.High = right.High
+		t.byHigh[left.High] = left
+	case left != nil:
+		delete(t.byHigh, left.High)
+		left.High = index + 1
+		t.byHigh[left.High] = left
+	case right != nil:
+		delete(t.byLow, right.Low)
+		right.Low = index
+		t.byLow[right.Low] = right
+	default:
+		r := &IndexRange{Low: index, High: index + 1}
+		t.byLow[r.Low], t.byHigh[r.High] = r, r
+	}
+}
+
+// snapshot Returns the completed ranges of the resumed checkpoint and of the trackers, merged in order,
+// with their sequence number.
+func (c *checkpointer) snapshot() (int, []IndexRange) {
+	c.mu.Lock()
+	c.seq++
+	seq := c.seq
+	ranges := append([]IndexRange(nil), c.resumed...)
+	for _, t := range c.trackers {
+		t.mu.Lock()
+		for _, r := range t.byLow {
+			ranges = append(ranges, *r)
+		}
+		t.mu.Unlock()
+	}
+	c.mu.Unlock()
+
+	sort.Slice(ranges, func(i, j int) bool { return ranges[i].Low < ranges[j].Low })
+	completed := make([]IndexRange, 0, len(ranges))
+	for _, r := range ranges {
+		if n := len(completed); n > 0 && r.Low <= completed[n-1].High {
+			if r.High > completed[n-1].High {
+				completed[n-1].High = r.High
+			}
+			continue
+		}
+		completed = append(completed, r)
+	}
+	return seq, completed
+}
+
+// save Saves the checkpoint, unless a later snapshot was already saved.
+func (c *checkpointer) save(seq int, completed []IndexRange, finished bool) {
+	c.saveMu.Lock()
+	defer c.saveMu.Unlock()
+	if seq < c.saved {
+		return
+	}
+	c.saved = seq
+	cp := Checkpoint{Size: c.size, Completed: completed, Finished: finished}
+	var err error
+	if c.config.o.save != nil {
+		cp.State, err = c.config.o.save()
+	}
+	if err == nil {
+		err = c.config.store.Save(cp)
+	}
+	if err != nil && c.err == nil {
+		c.err = err
+	}
+}
+
+// finish Saves the final checkpoint, finished if the evaluation has ended without a panic or an error of the source.
+// Returns the first error of the saves.
+func (c *checkpointer) finish(finished bool) error {
+	seq, completed := c.snapshot()
+	c.save(seq, completed, finished)
+	return c.err
+}
+
+// checkpointFlow Returns the flow running the handler over the elements not skipped by the checkpointer,
+// marking them by the tracker once processed.
+func checkpointFlow[E any, R any](c *checkpointer, t *checkpointTracker, handler flow[E, R]) flow[E, R] {
+	return func(index int, e E, emit func(R) bool) bool {
+		if c.skipped(index) {
+			return true
+		}
+		more := handler(index, e, emit)
+		c.mark(t, index)
+		return more
+	}
+}
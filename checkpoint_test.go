@@ -0,0 +1,196 @@
+package stream
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// memoryCheckpointStore Keeps the checkpoints in memory, counting the saves.
+type memoryCheckpointStore struct {
+	mu    sync.Mutex
+	cp    *Checkpoint
+	saves int
+	err   error
+}
+
+func (s *memoryCheckpointStore) Save(cp Checkpoint) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return s.err
+	}
+	s.cp = &cp
+	s.saves++
+	return nil
+}
+
+func (s *memoryCheckpointStore) Load() (Checkpoint, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.cp == nil {
+		return Checkpoint{}, false, nil
+	}
+	return *s.cp, true, nil
+}
+
+func TestFileCheckpointStore(t *testing.T) {
+	dir := t.TempDir()
+	store := NewFileCheckpointStore(filepath.Join(dir, "job.checkpoint"))
+	_, ok, err := store.Load()
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	cp := Checkpoint{Size: 10, Completed: []IndexRange{{Low: 0, High: 3}, {Low: 5, High: 7}}, State: []byte("state")}
+	assert.NoError(t, store.Save(cp))
+	assert.NoError(t, store.Save(cp))
+	got, ok, err := store.Load()
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, cp, got)
+
+	// Only the checkpoint file is left.
+	entries, err := os.ReadDir(dir)
+	assert.NoError(t, err)
+	assert.Len(t, entries, 1)
+
+	assert.NoError(t, store.Remove())
+	assert.NoError(t, store.Remove())
+	_, ok, _ = store.Load()
+	assert.False(t, ok)
+
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "job.checkpoint"), []byte("{"), 0o600))
+	_, _, err = store.Load()
+	assert.Error(t, err)
+	assert.Error(t, NewFileCheckpointStore(filepath.Join(dir, "missing", "job.checkpoint")).Save(cp))
+}
+
+func TestCheckpointResume(t *testing.T) {
+	source := make([]int, 1000)
+	for i := range source {
+		source[i] = i
+	}
+
+	for _, goroutines := range []int{0, 4} {
+		store := NewFileCheckpointStore(filepath.Join(t.TempDir(), "job.checkpoint"))
+		var processed [1000]int32
+
+		// The job crashes after about 600 elements.
+		var count int32
+		assert.Panics(t, func() {
+			NewSlice(source).
+				Checkpoint(store, CheckpointEvery(100)).
+				Parallel(goroutines).
+				ForEach(func(_ int, v int) {
+					if atomic.AddInt32(&count, 1) > 600 {
+						panic("crash")
+					}
+					atomic.AddInt32(&processed[v], 1)
+				})
+		})
+		cp, ok, err := store.Load()
+		assert.NoError(t, err)
+		assert.True(t, ok)
+		assert.Equal(t, 1000, cp.Size)
+		assert.False(t, cp.Finished)
+
+		// The resumed job processes the remaining elements.
+		resumed := NewSlice(source).
+			Checkpoint(store, CheckpointEvery(100)).
+			Parallel(goroutines).
+			ForEach(func(_ int, v int) { atomic.AddInt32(&processed[v], 1) })
+		assert.NoError(t, resumed.Err())
+		assert.Less(t, resumed.Count(), 1000)
+		for v, n := range processed {
+			assert.GreaterOrEqual(t, n, int32(1), v)
+		}
+
+		cp, _, _ = store.Load()
+		assert.Equal(t, []IndexRange{{Low: 0, High: 1000}}, cp.Completed)
+		assert.True(t, cp.Finished)
+
+		// The next run of a finished job processes all the elements again.
+		var again int32
+		NewSlice(source).Checkpoint(store).Parallel(goroutines).ForEach(func(int, int) { atomic.AddInt32(&again, 1) })
+		assert.Equal(t, int32(1000), again)
+		cp, _, _ = store.Load()
+		assert.Equal(t, []IndexRange{{Low: 0, High: 1000}}, cp.Completed)
+		assert.True(t, cp.Finished)
+	}
+}
+
+func TestCheckpointState(t *testing.T) {
+	store := &memoryCheckpointStore{}
+	var mu sync.Mutex
+	sum := 0
+	state := CheckpointState(
+		func() ([]byte, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			return []byte(strconv.Itoa(sum)), nil
+		},
+		func(state []byte) (err error) {
+			sum, err = strconv.Atoi(string(state))
+			return
+		})
+
+	assert.Panics(t, func() {
+		Range(0, 100, 1).Checkpoint(store, CheckpointEvery(1), state).ForEach(func(_ int, v int) {
+			if v == 50 {
+				panic("crash")
+			}
+			mu.Lock()
+			sum += v
+			mu.Unlock()
+		})
+	})
+	assert.Equal(t, []IndexRange{{Low: 0, High: 50}}, store.cp.Completed)
+
+	sum = -1
+	Range(0, 100, 1).Checkpoint(store, state).Parallel(3).ForEach(func(_ int, v int) {
+		mu.Lock()
+		sum += v
+		mu.Unlock()
+	})
+	assert.Equal(t, 99*100/2, sum)
+	assert.Equal(t, strconv.Itoa(sum), string(store.cp.State))
+
+	// Elements pulled from a channel are skipped in order.
+	store = &memoryCheckpointStore{cp: &Checkpoint{Size: -1, Completed: []IndexRange{{Low: 0, High: 3}, {Low: 5, High: 6}}}}
+	got := FromChan(sendAll([]int{0, 1, 2, 3, 4, 5, 6})).Checkpoint(store).Map(func(v int) int { return v * 10 }).ToSlice()
+	assert.Equal(t, []int{30, 40, 60}, got)
+	assert.Equal(t, []IndexRange{{Low: 0, High: 7}}, store.cp.Completed)
+}
+
+func TestCheckpointErrors(t *testing.T) {
+	store := &memoryCheckpointStore{cp: &Checkpoint{Size: 3}}
+	stream := NewSlice([]int{1, 2}).Checkpoint(store).Map(func(v int) int { return v })
+	assert.Empty(t, stream.ToSlice())
+	assert.ErrorIs(t, stream.Err(), ErrCheckpointMismatch)
+
+	errSave := errors.New("save")
+	store = &memoryCheckpointStore{err: errSave}
+	stream = NewSlice([]int{1, 2}).Checkpoint(store).Map(func(v int) int { return v })
+	assert.Equal(t, []int{1, 2}, stream.ToSlice())
+	assert.ErrorIs(t, stream.Err(), errSave)
+
+	errRestore := errors.New("restore")
+	store = &memoryCheckpointStore{cp: &Checkpoint{Size: 2}}
+	stream = NewSlice([]int{1, 2}).
+		Checkpoint(store, CheckpointState(nil, func([]byte) error { return errRestore })).
+		Map(func(v int) int { return v })
+	assert.Empty(t, stream.ToSlice())
+	assert.ErrorIs(t, stream.Err(), errRestore)
+
+	// The checkpoint only applies to the next evaluation.
+	store = &memoryCheckpointStore{}
+	stream = NewSlice([]int{1, 2, 3}).Checkpoint(store).ForEach(func(int, int) {})
+	assert.Equal(t, []int{1, 2, 3}, stream.Map(func(v int) int { return v }).ToSlice())
+	assert.Equal(t, 1, store.saves)
+}
@@ -0,0 +1,182 @@
+package stream
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// DecodeError The error of a record that can not be decoded.
+type DecodeError struct {
+	// Line The line number of the record, starting at 1.
+	Line int
+	Err  error
+}
+
+func (e *DecodeError) Error() string {
+	return fmt.Sprintf("stream: decode record at line %d: %v", e.Line, e.Err)
+}
+
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+// SkipDecodeErrors Skips the records that can not be decoded instead of stopping the source.
+// The handler, if not nil, is called with the error of every skipped record.
+func SkipDecodeErrors(handler func(err *DecodeError)) SourceOption {
+	return func(o *sourceOptions) {
+		o.skipErrors = true
+		o.onError = handler
+	}
+}
+
+// WithHeader The first record of a CSV source is a header, it is not decoded.
+func WithHeader() SourceOption {
+	return func(o *sourceOptions) {
+		o.header = true
+	}
+}
+
+// decoded new generator of the records decoded by next.
+// By default a *DecodeError stops the source, other errors always stop the source.
+func decoded[E any](o sourceOptions, next func() (e E, ok bool, err error)) *generator[E] {
+	gen := pulled[E](false, nil)
+	gen.batchSize = o.batchSize
+	gen.next = func() (e E, ok bool) {
+		for {
+			e, ok, err := next()
+			var decodeErr *DecodeError
+			if errors.As(err, &decodeErr) && o.skipErrors {
+				if o.onError != nil {
+					o.onError(decodeErr)
+				}
+				continue
+			}
+			if err != nil {
+				gen.err = err
+				return e, false
+			}
+			return e, ok
+		}
+	}
+	return gen
+}
+
+// FromJSONLines new stream instance of the JSON values decoded from the lines of the reader.
+// Blank lines are ignored, the reader is read and decoded lazily as the stream is evaluated.
+// A line that can not be decoded stops the source with a *DecodeError reported by Pipeline.Err,
+// unless SkipDecodeErrors is used.
+//
+// Support Parallel, records are read by batches, and the original order is kept.
+func FromJSONLines[E any](r io.Reader, opts ...SourceOption) SliceStream[E] {
+	o := newSourceOptions(opts)
+	lines := scanned(r, bufio.ScanLines, o, func(b []byte) []byte { return b })
+	line := 0
+	gen := decoded(o, func() (e E, ok bool, err error) {
+		for {
+			b, ok := lines.next()
+			if !ok {
+				return e, false, lines.err
+			}
+			line++
+			if len(bytes.TrimSpace(b)) == 0 {
+				continue
+			}
+			if err := json.Unmarshal(b, &e); err != nil {
+				return e, false, &DecodeError{Line: line, Err: err}
+			}
+			return e, true, nil
+		}
+	})
+	return newGenerated(gen)
+}
+
+// CSVMapping Decodes a CSV record into an element.
+type CSVMapping[E any] func(record []string) (E, error)
+
+// FromCSV new stream instance of the CSV records of the reader, decoded by the mapping.
+// Use WithHeader if the first record is a header, the reader is read and decoded lazily as the stream is evaluated.
+// A record that can not be parsed or mapped stops the source with a *DecodeError reported by Pipeline.Err,
+// unless SkipDecodeErrors is used.
+//
+// Support Parallel, records are read by batches, and the original order is kept.
+func FromCSV[E any](r io.Reader, mapping CSVMapping[E], opts ...SourceOption) SliceStream[E] {
+	o := newSourceOptions(opts)
+	reader := csv.NewReader(r)
+	header := o.header
+	gen := decoded(o, func() (e E, ok bool, err error) {
+		for {
+			record, err := reader.Read()
+			if err == io.EOF {
+				return e, false, nil
+			}
+			var parseErr *csv.ParseError
+			if errors.As(err, &parseErr) {
+				return e, false, &DecodeError{Line: parseErr.Line, Err: err}
+			}
+			if err != nil {
+				return e, false, err
+			}
+			if header {
+				header = false
+				continue
+			}
+
+			line, _ := reader.FieldPos(0)
+			e, err = mapping(record)
+			if err != nil {
+				return e, false, &DecodeError{Line: line, Err: err}
+			}
+			return e, true, nil
+		}
+	})
+	return newGenerated(gen)
+}
+
+// ToJSONLines Writes the elements of this stream to w as JSON values, one per line.
+// Returns the first encoding or write error, or the error that stopped the source of the stream.
+//
+// Support Parallel.
+func (stream SliceStream[E]) ToJSONLines(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	var err error
+	stream.eachBounded(func(e E) bool {
+		err = enc.Encode(e)
+		return err == nil
+	})
+	if err != nil {
+		return err
+	}
+	return stream.err
+}
+
+// ToCSV Writes the elements of this stream to w as CSV records, converted by the record function.
+// If header is not nil then it is written first.
+// Returns the first write error, or the error that stopped the source of the stream.
+//
+// Support Parallel.
+func (stream SliceStream[E]) ToCSV(w io.Writer, header []string, record func(E) []string) error {
+	writer := csv.NewWriter(w)
+	if header != nil {
+		if err := writer.Write(header); err != nil {
+			return err
+		}
+	}
+	var err error
+	stream.eachBounded(func(e E) bool {
+		err = writer.Write(record(e))
+		return err == nil
+	})
+	if err != nil {
+		return err
+	}
+	writer.Flush()
+	if err = writer.Error(); err != nil {
+		return err
+	}
+	return stream.err
+}
@@ -0,0 +1,122 @@
+package stream
+
+import (
+	"bytes"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+type codecRecord struct {
+	Name  string `json:"name"`
+	Price int    `json:"price"`
+}
+
+func codecMapping(record []string) (codecRecord, error) {
+	price, err := strconv.Atoi(record[1])
+	if err != nil {
+		return codecRecord{}, err
+	}
+	return codecRecord{Name: record[0], Price: price}, nil
+}
+
+func TestFromJSONLines(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []codecRecord
+	}{
+		{
+			name:  "case",
+			input: "{\"name\":\"a\",\"price\":1}\n\n{\"name\":\"b\",\"price\":2}\n",
+			want:  []codecRecord{{"a", 1}, {"b", 2}},
+		},
+		{
+			name:  "empty",
+			input: "",
+			want:  []codecRecord{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := FromJSONLines[codecRecord](strings.NewReader(tt.input)).ToSliceErr()
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+
+			got, err = FromJSONLines[codecRecord](strings.NewReader(tt.input)).Parallel(2).ToSliceErr()
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestFromJSONLinesDecodeError(t *testing.T) {
+	input := "{\"name\":\"a\",\"price\":1}\n{bad}\n{\"name\":\"c\",\"price\":3}\n"
+
+	got, err := FromJSONLines[codecRecord](strings.NewReader(input)).ToSliceErr()
+	var decodeErr *DecodeError
+	assert.True(t, errors.As(err, &decodeErr))
+	assert.Equal(t, 2, decodeErr.Line)
+	assert.Equal(t, []codecRecord{{"a", 1}}, got)
+
+	var skipped []int
+	got, err = FromJSONLines[codecRecord](strings.NewReader(input), SkipDecodeErrors(func(err *DecodeError) {
+		skipped = append(skipped, err.Line)
+	})).ToSliceErr()
+	assert.NoError(t, err)
+	assert.Equal(t, []codecRecord{{"a", 1}, {"c", 3}}, got)
+	assert.Equal(t, []int{2}, skipped)
+}
+
+func TestFromCSV(t *testing.T) {
+	input := "name,price\na,1\nb,x\nc,3\n"
+
+	got, err := FromCSV(strings.NewReader(input), codecMapping, WithHeader()).ToSliceErr()
+	var decodeErr *DecodeError
+	assert.True(t, errors.As(err, &decodeErr))
+	assert.Equal(t, 3, decodeErr.Line)
+	assert.Equal(t, []codecRecord{{"a", 1}}, got)
+
+	got, err = FromCSV(strings.NewReader(input), codecMapping, WithHeader(), SkipDecodeErrors(nil)).
+		Parallel(2).
+		Filter(func(r codecRecord) bool { return r.Price > 1 }).
+		ToSliceErr()
+	assert.NoError(t, err)
+	assert.Equal(t, []codecRecord{{"c", 3}}, got)
+
+	got, err = FromCSV(strings.NewReader("a,1\nb,2,extra\nc,3\n"), codecMapping, SkipDecodeErrors(nil)).ToSliceErr()
+	assert.NoError(t, err)
+	assert.Equal(t, []codecRecord{{"a", 1}, {"c", 3}}, got)
+}
+
+func TestToJSONLines(t *testing.T) {
+	var buf bytes.Buffer
+	err := NewSlice([]codecRecord{{"a", 1}, {"b", 2}}).
+		Parallel(2).
+		Filter(func(r codecRecord) bool { return r.Price > 1 }).
+		ToJSONLines(&buf)
+	assert.NoError(t, err)
+	assert.Equal(t, "{\"name\":\"b\",\"price\":2}\n", buf.String())
+
+	got := FromJSONLines[codecRecord](&buf).ToSlice()
+	assert.Equal(t, []codecRecord{{"b", 2}}, got)
+}
+
+func TestToCSV(t *testing.T) {
+	var buf bytes.Buffer
+	record := func(r codecRecord) []string { return []string{r.Name, strconv.Itoa(r.Price)} }
+	err := NewSlice([]codecRecord{{"a", 1}, {"b,c", 2}}).ToCSV(&buf, []string{"name", "price"}, record)
+	assert.NoError(t, err)
+	assert.Equal(t, "name,price\na,1\n\"b,c\",2\n", buf.String())
+
+	got := FromCSV(&buf, codecMapping, WithHeader()).ToSlice()
+	assert.Equal(t, []codecRecord{{"a", 1}, {"b,c", 2}}, got)
+
+	buf.Reset()
+	err = FromCSV(strings.NewReader("a,x\n"), codecMapping).ToCSV(&buf, nil, record)
+	var decodeErr *DecodeError
+	assert.True(t, errors.As(err, &decodeErr))
+	assert.Equal(t, "", buf.String())
+}
@@ -0,0 +1,234 @@
+package stream
+
+import (
+	"hash/maphash"
+	"math"
+	"reflect"
+	"sync"
+)
+
+// distinctCutoff The number of elements under which Distinct removes the duplicates sequentially, even with Parallel.
+const distinctCutoff = 1024
+
+// DistinctBy Returns a stream consisting of the elements of the stream with distinct keys.
+// The first element of each key is kept, in the original order.
+// See: SliceComparableStream.Distinct
+//
+// Support Parallel.
+func DistinctBy[E any, K comparable](stream SliceStream[E], key func(E) K) SliceStream[E] {
+	stream.addPushStage(StageInfo{Name: "DistinctBy"}, distinctStage(stream.Pipeline, key))
+	return stream
+}
+
+// distinctStage Returns a stateful push stage keeping the first element of each key.
+//
+// Sequentially, or if the size of the source is unknown, the elements are emitted as they are evaluated,
+// so the stage works on unbounded sources.
+// With Parallel, the elements are held until the end of the input, then hashed into shards deduplicated concurrently,
+// and the first occurrences are emitted in their original order.
+func distinctStage[E any, K comparable](pipe *Pipeline[E], key func(E) K) PushStage[E] {
+	var (
+		sharded bool
+		seen    map[K]struct{}
+		held    []E
+	)
+	return PushStage[E]{
+		Begin: func() {
+			sharded = pipe.parallelism() > 1 && (pipe.gen == nil || pipe.gen.size >= 0)
+			seen, held = map[K]struct{}{}, make([]E, 0)
+		},
+		Push: func(index int, e E, emit func(E) bool) bool {
+			if sharded {
+				held = append(held, e)
+				return true
+			}
+			k := key(e)
+			if _, ok := seen[k]; ok {
+				return true
+			}
+			seen[k] = struct{}{}
+			return emit(e)
+		},
+		Flush: func(emit func(E) bool) bool {
+			if !sharded {
+				return true
+			}
+			keep := distinctShards(held, key, pipe.parallelism(), pipe.executor)
+			for i, e := range held {
+				if keep[i] && !emit(e) {
+					return false
+				}
+			}
+			return true
+		},
+		Stateful: true,
+	}
+}
+
+// distinctShards Reports for each element whether it is the first of its key.
+// The keys are computed and hashed into shards by ranges of elements, then each shard is deduplicated by its own goroutine
+// visiting its elements in their original order.
+func distinctShards[E any, K comparable](elems []E, key func(E) K, goroutines int, executor Executor) []bool {
+	keep := make([]bool, len(elems))
+	if len(elems) < distinctCutoff {
+		seen := make(map[K]struct{}, len(elems))
+		for i, e := range elems {
+			k := key(e)
+			if _, ok := seen[k]; !ok {
+				seen[k] = struct{}{}
+				keep[i] = true
+			}
+		}
+		return keep
+	}
+
+	keys := make([]K, len(elems))
+	seed := maphash.MakeSeed()
+	parts := partition(len(elems), goroutines)
+	// buckets[r][s] The indexes of the elements of the range r hashed into the shard s, in order.
+	buckets := make([][][]int, len(parts))
+	var wg sync.WaitGroup
+	for r, pa := range parts {
+		r, pa := r, pa
+		wg.Add(1)
+		execute(executor, func() {
+			defer wg.Done()
+			var h maphash.Hash
+			h.SetSeed(seed)
+			buckets[r] = make([][]int, goroutines)
+			for i := pa.low; i < pa.high; i++ {
+				keys[i] = key(elems[i])
+				s := hashKey(&h, keys[i]) % uint64(goroutines)
+				buckets[r][s] = append(buckets[r][s], i)
+			}
+		})
+	}
+	wg.Wait()
+
+	for s := 0; s < goroutines; s++ {
+		s := s
+		wg.Add(1)
+		execute(executor, func() {
+			defer wg.Done()
+			seen := map[K]struct{}{}
+			for r := range buckets {
+				for _, i := range buckets[r][s] {
+					if _, ok := seen[keys[i]]; !ok {
+						seen[keys[i]] = struct{}{}
+						keep[i] = true
+					}
+				}
+			}
+		})
+	}
+	wg.Wait()
+	return keep
+}
+
+// hashKey Hashes the key, equal keys have the same hash.
+// The keys of types other than the basic types, such as structs, are hashed by their comparable parts. See: hashValue
+func hashKey[K comparable](h *maphash.Hash, k K) uint64 {
+	switch v := any(k).(type) {
+	case string:
+		h.Reset()
+		_, _ = h.WriteString(v)
+		return h.Sum64()
+	case int:
+		return mix64(uint64(v))
+	case int8:
+		return mix64(uint64(v))
+	case int16:
+		return mix64(uint64(v))
+	case int32:
+		return mix64(uint64(v))
+	case int64:
+		return mix64(uint64(v))
+	case uint:
+		return mix64(uint64(v))
+	case uint8:
+		return mix64(uint64(v))
+	case uint16:
+		return mix64(uint64(v))
+	case uint32:
+		return mix64(uint64(v))
+	case uint64:
+		return mix64(v)
+	case uintptr:
+		return mix64(uint64(v))
+	case float32:
+		return hashFloat(float64(v))
+	case float64:
+		return hashFloat(v)
+	case bool:
+		if v {
+			return 1
+		}
+		return 0
+	default:
+		h.Reset()
+		acc := hashValue(h, reflect.ValueOf(v), 0)
+		return mix64(acc ^ h.Sum64())
+	}
+}
+
+// hashValue Returns acc combined with the comparable parts of the value, the strings are written to h,
+// so equal values return the same hash once combined with h.Sum64.
+// The blank fields of structs are skipped as they are not compared, and 0 and -0 are combined the same.
+func hashValue(h *maphash.Hash, v reflect.Value, acc uint64) uint64 {
+	switch v.Kind() {
+	case reflect.String:
+		_, _ = h.WriteString(v.String())
+		_ = h.WriteByte(0)
+		return mix64(acc + uint64(v.Len()))
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return mix64(acc + uint64(v.Int()))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return mix64(acc + v.Uint())
+	case reflect.Float32, reflect.Float64:
+		return mix64(acc + hashFloat(v.Float()))
+	case reflect.Complex64, reflect.Complex128:
+		c := v.Complex()
+		return mix64(mix64(acc+hashFloat(real(c))) + hashFloat(imag(c)))
+	case reflect.Bool:
+		if v.Bool() {
+			return mix64(acc + 1)
+		}
+		return mix64(acc)
+	case reflect.Pointer, reflect.Chan, reflect.UnsafePointer:
+		return mix64(acc + uint64(v.Pointer()))
+	case reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			acc = hashValue(h, v.Index(i), acc)
+		}
+		return acc
+	case reflect.Struct:
+		t := v.Type()
+		for i := 0; i < v.NumField(); i++ {
+			if t.Field(i).Name != "_" {
+				acc = hashValue(h, v.Field(i), acc)
+			}
+		}
+		return acc
+	case reflect.Interface:
+		if v.IsNil() {
+			return mix64(acc)
+		}
+		return hashValue(h, v.Elem(), acc)
+	}
+	return acc
+}
+
+// hashFloat Hashes the number, 0 and -0 are equal so they have the same hash.
+func hashFloat(v float64) uint64 {
+	if v == 0 {
+		return 0
+	}
+	return mix64(math.Float64bits(v))
+}
+
+// mix64 Returns the splitmix64 mix of x.
+func mix64(x uint64) uint64 {
+	x = (x ^ (x >> 30)) * 0xbf58476d1ce4e5b9
+	x = (x ^ (x >> 27)) * 0x94d049bb133111eb
+	return x ^ (x >> 31)
+}
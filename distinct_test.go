@@ -0,0 +1,135 @@
+package stream
+
+import (
+	"github.com/stretchr/testify/assert"
+	"hash/maphash"
+	"math"
+	"math/rand"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+func TestSliceComparableDistinctParallel(t *testing.T) {
+	input := make([]int, 20000)
+	r := rand.New(rand.NewSource(1))
+	for i := range input {
+		input[i] = r.Intn(3000)
+	}
+	want := NewSliceByComparable(input).Distinct().ToSlice()
+	assert.Less(t, len(want), len(input))
+
+	for _, goroutines := range []int{2, 3, 8} {
+		got := NewSliceByComparable(input).Parallel(goroutines).Distinct().ToSlice()
+		assert.Equal(t, want, got)
+	}
+
+	strs := make([]string, 0, 5000)
+	for i := 0; i < 5000; i++ {
+		strs = append(strs, strconv.Itoa(i%1500))
+	}
+	distinct := NewSliceByComparable(strs).Parallel(4).Distinct().ToSlice()
+	assert.Equal(t, strs[:1500], distinct)
+}
+
+func TestSliceComparableDistinctUnbounded(t *testing.T) {
+	ch := make(chan int)
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		for i := 0; ; i++ {
+			select {
+			case ch <- i % 5:
+			case <-done:
+				return
+			}
+		}
+	}()
+	for _, goroutines := range []int{0, 4} {
+		stream := SliceOrderedStream[int]{SliceComparableStream: SliceComparableStream[int]{SliceStream: FromChan(ch)}}
+		distinct := stream.Parallel(goroutines).Distinct().Limit(5).Sort().ToSlice()
+		assert.Equal(t, []int{0, 1, 2, 3, 4}, distinct)
+	}
+}
+
+func TestDistinctBy(t *testing.T) {
+	type user struct {
+		Name string
+		Age  int
+	}
+	input := []user{{"a", 1}, {"b", 2}, {"c", 1}, {"d", 3}, {"e", 2}}
+	for _, goroutines := range []int{0, 2} {
+		got := DistinctBy(NewSlice(input).Parallel(goroutines), func(u user) int { return u.Age }).ToSlice()
+		assert.Equal(t, []user{{"a", 1}, {"b", 2}, {"d", 3}}, got)
+	}
+
+	many := make([]user, 0, 5000)
+	for i := 0; i < 5000; i++ {
+		many = append(many, user{Name: strconv.Itoa(i), Age: i % 100})
+	}
+	want := DistinctBy(NewSlice(many), func(u user) user { return user{Age: u.Age} }).ToSlice()
+	assert.Len(t, want, 100)
+	got := DistinctBy(NewSlice(many).Parallel(4), func(u user) user { return user{Age: u.Age} }).ToSlice()
+	assert.Equal(t, want, got)
+
+	assert.Nil(t, DistinctBy(NewSlice([]user(nil)), func(u user) int { return u.Age }).ToSlice())
+
+	// 0 and -0 are equal keys inside a struct, so they are deduplicated by the same shard.
+	type point struct {
+		X int
+		Y float64
+	}
+	points := make([]point, 0, 4000)
+	for i := 0; i < 2000; i++ {
+		points = append(points, point{X: i, Y: 0})
+	}
+	for i := 0; i < 2000; i++ {
+		points = append(points, point{X: i, Y: math.Copysign(0, -1)})
+	}
+	distinct := DistinctBy(NewSlice(points).Parallel(4), func(p point) point { return p }).ToSlice()
+	assert.Equal(t, points[:2000], distinct)
+}
+
+func TestDistinctInfinite(t *testing.T) {
+	for _, goroutines := range []int{0, 4} {
+		got := RangeFrom(0, 1).Parallel(goroutines).Map(func(v int) int { return v / 3 }).Distinct().Limit(4).ToSlice()
+		assert.Equal(t, []int{0, 1, 2, 3}, got)
+	}
+}
+
+func TestHashKey(t *testing.T) {
+	var h maphash.Hash
+	h.SetSeed(maphash.MakeSeed())
+	assert.Equal(t, hashKey(&h, "a"), hashKey(&h, "a"))
+	assert.Equal(t, hashKey(&h, 0.0), hashKey(&h, -0.0))
+	type point struct{ X, Y int }
+	assert.Equal(t, hashKey(&h, point{1, 2}), hashKey(&h, point{1, 2}))
+	assert.NotEqual(t, hashKey(&h, 1), hashKey(&h, 2))
+
+	// The comparable parts of a struct are hashed, equal keys have the same hash.
+	type key struct {
+		name  string
+		tags  [2]string
+		score float64
+		at    *point
+		_     int
+	}
+	p := &point{1, 2}
+	a := key{name: "a", tags: [2]string{"x", "y"}, score: 0.0, at: p}
+	b := key{name: "a", tags: [2]string{"x", "y"}, score: math.Copysign(0, -1), at: p}
+	assert.Equal(t, a, b)
+	assert.Equal(t, hashKey(&h, a), hashKey(&h, b))
+	b.tags[1] = "z"
+	assert.NotEqual(t, hashKey(&h, a), hashKey(&h, b))
+	assert.NotEqual(t, hashKey(&h, key{name: "ab"}), hashKey(&h, key{name: "a", tags: [2]string{"b"}}))
+	assert.Equal(t, hashKey(&h, key{}), hashKey(&h, key{}))
+
+	hashAny := func(v any) uint64 {
+		h.Reset()
+		acc := hashValue(&h, reflect.ValueOf(&v).Elem(), 0)
+		return mix64(acc ^ h.Sum64())
+	}
+	assert.Equal(t, hashAny(point{1, 2}), hashAny(point{1, 2}))
+	assert.Equal(t, hashAny(nil), hashAny(nil))
+	assert.NotEqual(t, hashAny(point{1, 2}), hashAny(point{2, 1}))
+}
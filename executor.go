@@ -0,0 +1,140 @@
+package stream
+
+import (
+	"context"
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+// Executor Runs the tasks of the parallel evaluations, the tasks do not panic.
+type Executor interface {
+	// Execute Runs the task asynchronously.
+	// The tasks of an evaluation may wait for their results to be consumed by the calling goroutine,
+	// so they must not be run by it, nor wait for each other to start.
+	Execute(task func())
+}
+
+// ExecutorFunc Adapter to use a function as an Executor.
+// For example ExecutorFunc(func(task func()) { go task() }) runs each task in a new goroutine.
+type ExecutorFunc func(task func())
+
+// Execute Calls f(task).
+func (f ExecutorFunc) Execute(task func()) {
+	f(task)
+}
+
+// Pool Executor reusing a bounded number of worker goroutines across the evaluations of streams.
+// The workers are started as they are needed, up to the size of the pool, then kept waiting for the next tasks.
+// If all the workers are busy then the task is run by a new goroutine,
+// so the tasks never wait for a worker and the nested parallel streams do not deadlock.
+type Pool struct {
+	size    int32
+	started int32
+	tasks   chan func()
+	mu      sync.RWMutex
+	closed  bool
+	wg      sync.WaitGroup
+}
+
+// NewPool new pool of at most size workers, if size < 1 then runtime.GOMAXPROCS(0) is used.
+func NewPool(size int) *Pool {
+	if size < 1 {
+		size = runtime.GOMAXPROCS(0)
+	}
+	return &Pool{size: int32(size), tasks: make(chan func())}
+}
+
+var (
+	defaultPool     *Pool
+	defaultPoolOnce sync.Once
+)
+
+// DefaultPool Returns the pool of the process, used by the streams without an executor.
+// Its size is runtime.GOMAXPROCS(0), so few idle workers are kept alive,
+// the streams with more goroutines for IO operations run the other tasks by new goroutines.
+func DefaultPool() *Pool {
+	defaultPoolOnce.Do(func() {
+		defaultPool = NewPool(0)
+	})
+	return defaultPool
+}
+
+// Execute Runs the task by an idle worker, or by a new worker if the pool is not full, otherwise by a new goroutine
+// that is not kept as a worker. After Shutdown the task is run by a new goroutine.
+func (p *Pool) Execute(task func()) {
+	p.mu.RLock()
+	if p.closed {
+		p.mu.RUnlock()
+		go task()
+		return
+	}
+	select {
+	case p.tasks <- task:
+		p.mu.RUnlock()
+		return
+	default:
+	}
+	if atomic.AddInt32(&p.started, 1) <= p.size {
+		p.wg.Add(1)
+		go p.work(task)
+		p.mu.RUnlock()
+		return
+	}
+	atomic.AddInt32(&p.started, -1)
+	p.mu.RUnlock()
+	go task()
+}
+
+// Workers Returns the number of started workers.
+func (p *Pool) Workers() int {
+	return int(atomic.LoadInt32(&p.started))
+}
+
+// Shutdown Stops the workers once they have run their current task, then waits for them until the context is done.
+// Returns the error of the context if the workers did not stop in time.
+func (p *Pool) Shutdown(ctx context.Context) error {
+	p.mu.Lock()
+	if !p.closed {
+		p.closed = true
+		close(p.tasks)
+	}
+	p.mu.Unlock()
+
+	stopped := make(chan struct{})
+	go func() {
+		p.wg.Wait()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (p *Pool) work(task func()) {
+	defer func() {
+		atomic.AddInt32(&p.started, -1)
+		p.wg.Done()
+	}()
+	task()
+	for task := range p.tasks {
+		task()
+	}
+}
+
+// WithExecutor Runs the parallel evaluations of the stream by the executor, DefaultPool if executor is nil.
+func (stream SliceStream[E]) WithExecutor(executor Executor) SliceStream[E] {
+	stream.executor = executor
+	return stream
+}
+
+// execute Runs the task by the executor, or by DefaultPool if it is nil.
+func execute(executor Executor, task func()) {
+	if executor == nil {
+		executor = DefaultPool()
+	}
+	executor.Execute(task)
+}
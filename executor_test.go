@@ -0,0 +1,144 @@
+package stream
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestPool(t *testing.T) {
+	pool := NewPool(2)
+	var wg sync.WaitGroup
+	var n int64
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		pool.Execute(func() {
+			defer wg.Done()
+			atomic.AddInt64(&n, 1)
+		})
+	}
+	wg.Wait()
+	assert.Equal(t, int64(100), n)
+	assert.LessOrEqual(t, pool.Workers(), 2)
+
+	assert.NoError(t, pool.Shutdown(context.Background()))
+	assert.Equal(t, 0, pool.Workers())
+
+	done := make(chan struct{})
+	pool.Execute(func() { close(done) })
+	<-done
+}
+
+func TestPoolOverflow(t *testing.T) {
+	pool := NewPool(1)
+	release := make(chan struct{})
+	started := make(chan struct{})
+	pool.Execute(func() {
+		close(started)
+		<-release
+	})
+	<-started
+
+	// A saturated pool runs the task by a new goroutine, the calling goroutine does not wait for it.
+	ran := make(chan struct{})
+	pool.Execute(func() {
+		<-release
+		close(ran)
+	})
+	assert.Equal(t, 1, pool.Workers())
+	close(release)
+	<-ran
+	assert.NoError(t, pool.Shutdown(context.Background()))
+}
+
+func TestPoolShutdownTimeout(t *testing.T) {
+	pool := NewPool(1)
+	release := make(chan struct{})
+	started := make(chan struct{})
+	pool.Execute(func() {
+		close(started)
+		<-release
+	})
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, pool.Shutdown(ctx), context.DeadlineExceeded)
+	close(release)
+	assert.NoError(t, pool.Shutdown(context.Background()))
+}
+
+func TestSliceWithExecutor(t *testing.T) {
+	pool := NewPool(4)
+	defer pool.Shutdown(context.Background())
+
+	var executed int64
+	counting := ExecutorFunc(func(task func()) {
+		atomic.AddInt64(&executed, 1)
+		pool.Execute(task)
+	})
+	for i := 0; i < 10; i++ {
+		got := NewSlice([]int{1, 2, 3, 4, 5, 6, 7, 8}).
+			WithExecutor(counting).
+			Parallel(4).
+			Map(func(v int) int { return v * 2 }).
+			ToSlice()
+		assert.Equal(t, []int{2, 4, 6, 8, 10, 12, 14, 16}, got)
+	}
+	assert.Equal(t, int64(40), executed)
+	assert.LessOrEqual(t, pool.Workers(), 4)
+
+	// Nested parallel streams run by a saturated pool do not deadlock.
+	got := NewSlice([]int{1, 2, 3, 4}).
+		WithExecutor(pool).
+		Parallel(4).
+		Map(func(v int) int {
+			return NewSlice([]int{v, v, v}).WithExecutor(pool).Parallel(3).Reduce(0, func(r, e int) int { return r + e })
+		}).
+		ToSlice()
+	assert.Equal(t, []int{3, 6, 9, 12}, got)
+
+	assert.Panics(t, func() {
+		NewSlice([]int{1, 2, 3}).WithExecutor(pool).Parallel(3).ForEach(func(int, int) { panic("boom") })
+	})
+}
+
+func TestDefaultPool(t *testing.T) {
+	var running int32
+	NewSlice(make([]int, 200)).Parallel(200).ForEach(func(int, int) {
+		atomic.AddInt32(&running, 1)
+		for atomic.LoadInt32(&running) < 200 {
+			runtime.Gosched()
+		}
+	})
+	// The tasks beyond the size of the pool are run by new goroutines, not kept as workers.
+	assert.LessOrEqual(t, DefaultPool().Workers(), runtime.GOMAXPROCS(0))
+}
+
+func TestPoolExpandingStage(t *testing.T) {
+	pool := NewPool(1)
+	defer pool.Shutdown(context.Background())
+
+	// Each partition produces more results than its buffer holds, while the pool is saturated.
+	stream := NewSlice(make([]int, 1000)).WithExecutor(pool).Parallel(4)
+	stream.AddPushStage(PushStage[int]{Push: func(_ int, _ int, emit func(int) bool) bool {
+		for j := 0; j < 100; j++ {
+			if !emit(j) {
+				return false
+			}
+		}
+		return true
+	}})
+	done := make(chan int)
+	go func() { done <- len(stream.ToSlice()) }()
+	select {
+	case n := <-done:
+		assert.Equal(t, 100000, n)
+	case <-time.After(10 * time.Second):
+		t.Fatal("the parallel evaluation deadlocked")
+	}
+}
@@ -0,0 +1,74 @@
+package stream
+
+import (
+	"fmt"
+	"strings"
+)
+
+// StageInfo Metadata of an operation added to the stream.
+type StageInfo struct {
+	// ID The position of the operation in the stream, starting at 0.
+	ID int
+	// Name The operation name, e.g. Filter, Map, Sort.
+	Name string
+	// Label Optional user label. See: SliceStream.Label
+	Label string
+	// ShortCircuit The operation may complete before all elements are processed.
+	ShortCircuit bool
+	// Stateful The operation needs to see other elements of the stream, e.g. Sort, Distinct.
+	Stateful bool
+	// Evaluation The operation forces evaluation() of all the stages added before it.
+	Evaluation bool
+}
+
+// String Returns the name of the operation followed by its label and attributes.
+func (info StageInfo) String() string {
+	var b strings.Builder
+	b.WriteString(info.Name)
+	if info.Label != "" {
+		fmt.Fprintf(&b, " %q", info.Label)
+	}
+
+	var attrs []string
+	if info.Evaluation {
+		attrs = append(attrs, "evaluation")
+	}
+	if info.ShortCircuit {
+		attrs = append(attrs, "short-circuit")
+	}
+	if info.Stateful {
+		attrs = append(attrs, "stateful")
+	}
+	if len(attrs) > 0 {
+		fmt.Fprintf(&b, " [%s]", strings.Join(attrs, ", "))
+	}
+	return b.String()
+}
+
+// Stages Returns the operations added to the stream, in the order they were added.
+func (pipe *Pipeline[E]) Stages() []StageInfo {
+	stages := make([]StageInfo, len(pipe.plan))
+	copy(stages, pipe.plan)
+	return stages
+}
+
+// Explain Returns a human-readable plan of the operations added to the stream.
+// Operations marked with `evaluation` run all the stages added before them,
+// stages between two evaluations are fused into a single loop over the elements.
+func (pipe *Pipeline[E]) Explain() string {
+	var b strings.Builder
+	if pipe.goroutines == Auto {
+		b.WriteString("Pipeline (parallel: auto)\n")
+	} else if pipe.goroutines > 1 {
+		fmt.Fprintf(&b, "Pipeline (parallel: %d goroutines)\n", pipe.goroutines)
+	} else {
+		b.WriteString("Pipeline (sequential)\n")
+	}
+	if len(pipe.plan) == 0 {
+		b.WriteString("  (no stages)\n")
+	}
+	for i, info := range pipe.plan {
+		fmt.Fprintf(&b, "  %d. %s\n", i+1, info)
+	}
+	return b.String()
+}
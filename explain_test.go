@@ -0,0 +1,83 @@
+package stream
+
+import (
+	"github.com/stretchr/testify/assert"
+	"strconv"
+	"testing"
+)
+
+func TestExplain(t *testing.T) {
+	tests := []struct {
+		name   string
+		stream func() string
+		want   string
+	}{
+		{
+			name: "case",
+			stream: func() string {
+				return NewSlice([]int{3, 1, 2}).
+					Filter(func(v int) bool { return v > 1 }).Label("gt1").
+					Map(func(v int) int { return v * 2 }).
+					SortFunc(func(a, b int) bool { return a < b }).
+					Limit(1).
+					Explain()
+			},
+			want: "Pipeline (sequential)\n" +
+				"  1. Filter \"gt1\"\n" +
+				"  2. Map\n" +
+				"  3. SortFunc [stateful]\n" +
+				"  4. Limit [short-circuit, stateful]\n",
+		},
+		{
+			name: "case",
+			stream: func() string {
+				return NewSliceByOrdered([]int{3, 1, 2}).
+					Parallel(4).
+					Map(func(v int) int { return v * 2 }).
+					Distinct().Label("dedup").
+					Sort().
+					Explain()
+			},
+			want: "Pipeline (parallel: 4 goroutines)\n" +
+				"  1. Map\n" +
+				"  2. Distinct \"dedup\" [stateful]\n" +
+				"  3. Sort [stateful]\n",
+		},
+		{
+			name: "case",
+			stream: func() string {
+				return NewSliceByMapping[int, string, string]([]int{3, 1, 2}).
+					Filter(func(v int) bool { return v > 1 }).
+					Map(func(v int) string { return strconv.Itoa(v) }).
+					Explain()
+			},
+			want: "Pipeline (sequential)\n" +
+				"  1. Filter\n" +
+				"  2. Map [evaluation]\n",
+		},
+		{
+			name: "empty",
+			stream: func() string {
+				return NewSlice([]int{}).Label("ignored").Explain()
+			},
+			want: "Pipeline (sequential)\n" +
+				"  (no stages)\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.stream())
+		})
+	}
+}
+
+func TestStages(t *testing.T) {
+	s := NewSlice([]int{1, 2, 3}).Filter(func(v int) bool { return v > 1 }).Label("gt1")
+	assert.Equal(t, []StageInfo{{ID: 0, Name: "Filter", Label: "gt1"}}, s.Stages())
+
+	s.ForEach(func(int, int) {})
+	assert.Equal(t, []StageInfo{
+		{ID: 0, Name: "Filter", Label: "gt1"},
+		{ID: 1, Name: "ForEach", Evaluation: true},
+	}, s.Stages())
+}
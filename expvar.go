@@ -0,0 +1,54 @@
+package stream
+
+import (
+	"expvar"
+	"strconv"
+)
+
+// ExpvarObserver Observer that exports the metrics of stages and partitions as an expvar.Map.
+//
+// Every stage is exported with the keys `<stage>.in`, `<stage>.out`, `<stage>.dropped` and `<stage>.duration_ns`,
+// where `<stage>` is the label of the stage, or its name and ID if it has no label.
+// Partitions are exported with the keys `partitions` and `partitions.duration_ns`.
+type ExpvarObserver struct {
+	vars *expvar.Map
+}
+
+// NewExpvarObserver new Observer exporting the metrics under the expvar name.
+// If a expvar.Map is already published under the name, the metrics are added to it.
+func NewExpvarObserver(name string) *ExpvarObserver {
+	vars, ok := expvar.Get(name).(*expvar.Map)
+	if !ok {
+		vars = expvar.NewMap(name)
+	}
+	return &ExpvarObserver{vars: vars}
+}
+
+// Map Returns the exported expvar.Map.
+func (o *ExpvarObserver) Map() *expvar.Map {
+	return o.vars
+}
+
+// OnStageStart See: Observer.OnStageStart
+func (o *ExpvarObserver) OnStageStart(StageInfo) {}
+
+// OnElement See: Observer.OnElement
+func (o *ExpvarObserver) OnElement(StageInfo, int, bool) {}
+
+// OnStageEnd See: Observer.OnStageEnd
+func (o *ExpvarObserver) OnStageEnd(stage StageInfo, stats StageStats) {
+	key := stage.Label
+	if key == "" {
+		key = stage.Name + "#" + strconv.Itoa(stage.ID)
+	}
+	o.vars.Add(key+".in", stats.In)
+	o.vars.Add(key+".out", stats.Out)
+	o.vars.Add(key+".dropped", stats.Dropped)
+	o.vars.Add(key+".duration_ns", int64(stats.Duration))
+}
+
+// OnPartitionDone See: Observer.OnPartitionDone
+func (o *ExpvarObserver) OnPartitionDone(stats PartitionStats) {
+	o.vars.Add("partitions", 1)
+	o.vars.Add("partitions.duration_ns", int64(stats.Duration))
+}
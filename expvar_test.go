@@ -0,0 +1,28 @@
+package stream
+
+import (
+	"expvar"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestExpvarObserver(t *testing.T) {
+	o := NewExpvarObserver("stream_test_expvar")
+	NewSlice([]int{1, 2, 3, 4}).
+		Parallel(2).
+		Observe(o).
+		Filter(func(v int) bool { return v > 1 }).Label("gt1").
+		Map(func(v int) int { return v * 2 }).
+		ToSlice()
+
+	assert.Equal(t, "4", o.Map().Get("gt1.in").String())
+	assert.Equal(t, "3", o.Map().Get("gt1.out").String())
+	assert.Equal(t, "1", o.Map().Get("gt1.dropped").String())
+	assert.Equal(t, "3", o.Map().Get("Map#1.in").String())
+	assert.Equal(t, "2", o.Map().Get("partitions").String())
+
+	// Reuses the published map
+	o2 := NewExpvarObserver("stream_test_expvar")
+	assert.Same(t, o.Map(), o2.Map())
+	assert.Same(t, o.Map(), expvar.Get("stream_test_expvar"))
+}
@@ -0,0 +1,369 @@
+package stream
+
+import (
+	"bufio"
+	"container/heap"
+	"encoding/gob"
+	"errors"
+	"io"
+	"os"
+
+	"golang.org/x/exp/slices"
+)
+
+// Encoder Encodes the elements written to a temporary file of ExternalSort.
+type Encoder[E any] interface {
+	Encode(e E) error
+}
+
+// Decoder Decodes the elements read from a temporary file of ExternalSort, io.EOF is returned when no element is left.
+type Decoder[E any] interface {
+	Decode() (E, error)
+}
+
+// Codec Creates the encoders and decoders of the temporary files of ExternalSort.
+type Codec[E any] interface {
+	NewEncoder(w io.Writer) Encoder[E]
+	NewDecoder(r io.Reader) Decoder[E]
+}
+
+// GobCodec Returns a Codec based on encoding/gob, the default codec of ExternalSort.
+func GobCodec[E any]() Codec[E] {
+	return gobCodec[E]{}
+}
+
+type gobCodec[E any] struct{}
+
+func (gobCodec[E]) NewEncoder(w io.Writer) Encoder[E] {
+	return gobEncoder[E]{enc: gob.NewEncoder(w)}
+}
+
+func (gobCodec[E]) NewDecoder(r io.Reader) Decoder[E] {
+	return gobDecoder[E]{dec: gob.NewDecoder(r)}
+}
+
+type gobEncoder[E any] struct {
+	enc *gob.Encoder
+}
+
+func (e gobEncoder[E]) Encode(elem E) error {
+	return e.enc.Encode(elem)
+}
+
+type gobDecoder[E any] struct {
+	dec *gob.Decoder
+}
+
+func (d gobDecoder[E]) Decode() (elem E, err error) {
+	err = d.dec.Decode(&elem)
+	return
+}
+
+// ExternalSort Returns a sorted stream consisting of the elements of this stream, for the sources that do not fit in memory.
+// The elements are sorted by runs of at most memoryLimit elements, each run is spilled to a temporary file of tmpDir
+// encoded by the codec, then the runs are merged lazily as the returned stream is evaluated.
+// At most 64 runs are merged at once, more runs are first merged by groups into longer runs.
+//
+// If codec is nil then GobCodec is used, if tmpDir is empty then os.TempDir is used.
+// If all the elements fit in memoryLimit then they are sorted in memory and no file is written.
+// The sort is stable, the temporary files are removed once the returned stream is evaluated.
+// An error of the temporary files stops the stream, it is reported by Pipeline.Err or ToSliceErr.
+func (stream SliceStream[E]) ExternalSort(less func(a, b E) bool, codec Codec[E], memoryLimit int, tmpDir string) SliceStream[E] {
+	stream.record(StageInfo{Name: "ExternalSort", Stateful: true, Evaluation: true})
+	if stream.source == nil && stream.gen == nil {
+		return stream
+	}
+	if codec == nil {
+		codec = GobCodec[E]()
+	}
+	if memoryLimit < 1 {
+		memoryLimit = 1
+	}
+
+	sorter := &externalSorter[E]{less: less, codec: codec, tmpDir: tmpDir}
+	elems := make([]E, 0)
+	stream.each(func(e E) bool {
+		elems = append(elems, e)
+		if len(elems) < memoryLimit {
+			return true
+		}
+		sorter.err = sorter.spill(elems)
+		elems = elems[:0]
+		return sorter.err == nil
+	})
+	if sorter.err == nil && stream.err != nil {
+		sorter.err = stream.err
+	}
+	if sorter.err != nil {
+		sorter.remove()
+		stream.err = sorter.err
+		stream.source = []E{}
+		return stream
+	}
+
+	slices.SortStableFunc(elems, less)
+	if len(sorter.paths) == 0 {
+		stream.source = elems
+		return stream
+	}
+	stream.source = nil
+	stream.gen = sorter.merge(elems)
+	return stream
+}
+
+// externalSorter Spills the sorted runs of ExternalSort to temporary files and merges them.
+type externalSorter[E any] struct {
+	less   func(a, b E) bool
+	codec  Codec[E]
+	tmpDir string
+	paths  []string
+	files  []*os.File
+	err    error
+}
+
+// spill Sorts the run and writes it to a new temporary file.
+func (s *externalSorter[E]) spill(run []E) (err error) {
+	slices.SortStableFunc(run, s.less)
+	f, err := os.CreateTemp(s.tmpDir, "stream-sort-*")
+	if err != nil {
+		return err
+	}
+	s.paths = append(s.paths, f.Name())
+	defer func() {
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+	}()
+
+	w := bufio.NewWriter(f)
+	enc := s.codec.NewEncoder(w)
+	for _, e := range run {
+		if err = enc.Encode(e); err != nil {
+			return err
+		}
+	}
+	return w.Flush()
+}
+
+// mergeFanIn The maximum number of runs merged at once, so the number of open files is bounded.
+// If there are more runs then they are merged by groups into longer runs first.
+const mergeFanIn = 64
+
+// merge new generator merging the spilled runs and the last run kept in memory, in the order of their elements.
+// Equal elements are returned in the order of their runs, so the merge is stable.
+// An error reading a run stops the generator after the element already read.
+func (s *externalSorter[E]) merge(last []E) *generator[E] {
+	var h *runHeap[E]
+	var failed error
+	gen := pulled[E](false, nil)
+	gen.next = func() (e E, ok bool) {
+		if failed != nil {
+			gen.err = failed
+			return
+		}
+		if h == nil {
+			if gen.err = s.compact(); gen.err != nil {
+				return
+			}
+			if h, gen.err = s.open(s.paths, last); gen.err != nil {
+				return
+			}
+		}
+		if h.Len() == 0 {
+			return
+		}
+		e, failed = h.pop()
+		return e, true
+	}
+	gen.close = s.remove
+	return gen
+}
+
+// compact Merges the spilled runs by groups of mergeFanIn into new spilled runs,
+// until they can be merged at once with the last run.
+func (s *externalSorter[E]) compact() error {
+	for len(s.paths)+1 > mergeFanIn {
+		var paths []string
+		for low := 0; low < len(s.paths); low += mergeFanIn {
+			high := low + mergeFanIn
+			if high > len(s.paths) {
+				high = len(s.paths)
+			}
+			if high-low == 1 {
+				paths = append(paths, s.paths[low])
+				continue
+			}
+			path, err := s.mergeFiles(s.paths[low:high])
+			if path != "" {
+				paths = append(paths, path)
+			}
+			if err != nil {
+				s.paths = append(paths, s.paths[low:]...)
+				return err
+			}
+		}
+		s.paths = paths
+	}
+	return nil
+}
+
+// mergeFiles Merges the spilled runs at paths into a new spilled run, then removes them.
+// Returns the path of the new run if it was created.
+func (s *externalSorter[E]) mergeFiles(paths []string) (path string, err error) {
+	h, err := s.open(paths, nil)
+	if err != nil {
+		return "", err
+	}
+	f, err := os.CreateTemp(s.tmpDir, "stream-sort-*")
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+	}()
+
+	w := bufio.NewWriter(f)
+	enc := s.codec.NewEncoder(w)
+	for h.Len() > 0 {
+		e, perr := h.pop()
+		if err = enc.Encode(e); err != nil {
+			return f.Name(), err
+		}
+		if perr != nil {
+			return f.Name(), perr
+		}
+	}
+	if err = w.Flush(); err != nil {
+		return f.Name(), err
+	}
+	s.closeFiles()
+	for _, p := range paths {
+		_ = os.Remove(p)
+	}
+	return f.Name(), nil
+}
+
+// open Opens the spilled runs at paths followed by the last run, and pushes the first element of each run to the heap.
+func (s *externalSorter[E]) open(paths []string, last []E) (*runHeap[E], error) {
+	h := &runHeap[E]{less: s.less}
+	runs := make([]*sortedRun[E], 0, len(paths)+1)
+	for _, path := range paths {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		s.files = append(s.files, f)
+		dec := s.codec.NewDecoder(bufio.NewReader(f))
+		runs = append(runs, &sortedRun[E]{order: len(runs), next: func() (E, bool, error) {
+			e, err := dec.Decode()
+			if errors.Is(err, io.EOF) {
+				return e, false, nil
+			}
+			return e, err == nil, err
+		}})
+	}
+	i := 0
+	runs = append(runs, &sortedRun[E]{order: len(runs), next: func() (e E, ok bool, err error) {
+		if i >= len(last) {
+			return
+		}
+		i++
+		return last[i-1], true, nil
+	}})
+
+	for _, r := range runs {
+		more, err := r.advance()
+		if err != nil {
+			return nil, err
+		}
+		if more {
+			heap.Push(h, r)
+		}
+	}
+	return h, nil
+}
+
+// closeFiles Closes the opened runs.
+func (s *externalSorter[E]) closeFiles() {
+	for _, f := range s.files {
+		_ = f.Close()
+	}
+	s.files = nil
+}
+
+// remove Closes and removes the temporary files.
+func (s *externalSorter[E]) remove() {
+	s.closeFiles()
+	for _, path := range s.paths {
+		_ = os.Remove(path)
+	}
+	s.paths = nil
+}
+
+// sortedRun A sorted run of ExternalSort, head is its next element.
+type sortedRun[E any] struct {
+	order int
+	head  E
+	next  func() (E, bool, error)
+}
+
+// advance Reads the next element of the run into head, returns false when the run is exhausted.
+func (r *sortedRun[E]) advance() (bool, error) {
+	e, ok, err := r.next()
+	if err != nil || !ok {
+		return false, err
+	}
+	r.head = e
+	return true, nil
+}
+
+// runHeap Min-heap of the runs by their head, implements heap.Interface.
+type runHeap[E any] struct {
+	runs []*sortedRun[E]
+	less func(a, b E) bool
+}
+
+func (h *runHeap[E]) Len() int {
+	return len(h.runs)
+}
+
+func (h *runHeap[E]) Less(i, j int) bool {
+	a, b := h.runs[i], h.runs[j]
+	if h.less(a.head, b.head) {
+		return true
+	}
+	if h.less(b.head, a.head) {
+		return false
+	}
+	return a.order < b.order
+}
+
+func (h *runHeap[E]) Swap(i, j int) {
+	h.runs[i], h.runs[j] = h.runs[j], h.runs[i]
+}
+
+func (h *runHeap[E]) Push(x any) {
+	h.runs = append(h.runs, x.(*sortedRun[E]))
+}
+
+func (h *runHeap[E]) Pop() any {
+	r := h.runs[len(h.runs)-1]
+	h.runs = h.runs[:len(h.runs)-1]
+	return r
+}
+
+// pop Returns the least head of the runs and advances its run.
+// If the run fails to read its next element then the head is returned with the error, and the run is removed.
+func (h *runHeap[E]) pop() (E, error) {
+	r := h.runs[0]
+	e := r.head
+	more, err := r.advance()
+	if more {
+		heap.Fix(h, 0)
+	} else {
+		heap.Pop(h)
+	}
+	return e, err
+}
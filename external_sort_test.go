@@ -0,0 +1,264 @@
+package stream
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSliceExternalSort(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       []int
+		memoryLimit int
+		goroutines  int
+		want        []int
+	}{
+		{
+			name:        "in memory",
+			input:       []int{5, 3, 1, 4, 2},
+			memoryLimit: 10,
+			want:        []int{1, 2, 3, 4, 5},
+		},
+		{
+			name:        "spilled",
+			input:       []int{9, 5, 3, 8, 1, 7, 4, 2, 6, 0},
+			memoryLimit: 3,
+			want:        []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9},
+		},
+		{
+			name:        "parallel",
+			input:       Range(999, -1, -1).ToSlice(),
+			memoryLimit: 100,
+			goroutines:  4,
+			want:        Range(0, 1000, 1).ToSlice(),
+		},
+		{
+			name:        "empty",
+			input:       []int{},
+			memoryLimit: 3,
+			want:        []int{},
+		},
+		{
+			name:        "nil",
+			input:       nil,
+			memoryLimit: 3,
+			want:        nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			got := NewSlice(tt.input).
+				Parallel(tt.goroutines).
+				ExternalSort(func(a, b int) bool { return a < b }, nil, tt.memoryLimit, dir).
+				ToSlice()
+			assert.Equal(t, tt.want, got)
+			assertEmptyDir(t, dir)
+		})
+	}
+}
+
+func TestSliceExternalSortStable(t *testing.T) {
+	type item struct {
+		Key   int
+		Order int
+	}
+	input := make([]item, 0, 20)
+	for i := 0; i < 20; i++ {
+		input = append(input, item{Key: i % 3, Order: i})
+	}
+	got := NewSlice(input).
+		ExternalSort(func(a, b item) bool { return a.Key < b.Key }, nil, 4, t.TempDir()).
+		ToSlice()
+	for i := 1; i < len(got); i++ {
+		if got[i-1].Key == got[i].Key {
+			assert.Less(t, got[i-1].Order, got[i].Order)
+		} else {
+			assert.Less(t, got[i-1].Key, got[i].Key)
+		}
+	}
+}
+
+func TestSliceExternalSortFanIn(t *testing.T) {
+	type item struct {
+		Key   int
+		Order int
+	}
+	n := 3*mergeFanIn + 10
+	input := make([]item, 0, n)
+	for i := 0; i < n; i++ {
+		input = append(input, item{Key: (n - i) % 7, Order: i})
+	}
+	dir := t.TempDir()
+	codec := &countingCodec[item]{}
+	got := NewSlice(input).
+		ExternalSort(func(a, b item) bool { return a.Key < b.Key }, codec, 1, dir).
+		ToSlice()
+	assert.Len(t, got, n)
+	for i := 1; i < len(got); i++ {
+		if got[i-1].Key == got[i].Key {
+			assert.Less(t, got[i-1].Order, got[i].Order)
+		} else {
+			assert.Less(t, got[i-1].Key, got[i].Key)
+		}
+	}
+	// The runs are merged by groups, so at most mergeFanIn runs are read at once.
+	assert.LessOrEqual(t, codec.max, mergeFanIn)
+	assertEmptyDir(t, dir)
+}
+
+func TestSliceExternalSortCorruptRun(t *testing.T) {
+	dir := t.TempDir()
+	sorted := NewSlice([]int{6, 5, 4, 3, 2, 1}).
+		ExternalSort(func(a, b int) bool { return a < b }, jsonCodec[int]{}, 2, dir)
+	entries, err := os.ReadDir(dir)
+	assert.NoError(t, err)
+	assert.Len(t, entries, 3)
+	for _, entry := range entries {
+		f, err := os.OpenFile(filepath.Join(dir, entry.Name()), os.O_APPEND|os.O_WRONLY, 0)
+		assert.NoError(t, err)
+		_, err = f.WriteString("corrupt")
+		assert.NoError(t, err)
+		assert.NoError(t, f.Close())
+	}
+
+	// The element read before the corrupt data is kept.
+	got, err := sorted.ToSliceErr()
+	assert.Error(t, err)
+	assert.Equal(t, []int{1, 2}, got)
+	assertEmptyDir(t, dir)
+}
+
+func TestSliceExternalSortReader(t *testing.T) {
+	dir := t.TempDir()
+	input := strings.NewReader("pear\napple\nfig\nbanana\ncherry\n")
+	got, err := FromReader(input, nil).
+		ExternalSort(func(a, b string) bool { return a < b }, jsonCodec[string]{}, 2, dir).
+		Limit(3).
+		ToSliceErr()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"apple", "banana", "cherry"}, got)
+	assertEmptyDir(t, dir)
+}
+
+func TestSliceExternalSortError(t *testing.T) {
+	dir := t.TempDir()
+	got, err := NewSlice([]int{3, 2, 1}).
+		ExternalSort(func(a, b int) bool { return a < b }, failingCodec[int]{}, 1, dir).
+		ToSliceErr()
+	assert.Error(t, err)
+	assert.Empty(t, got)
+	assertEmptyDir(t, dir)
+
+	got, err = NewSlice([]int{3, 2, 1}).
+		ExternalSort(func(a, b int) bool { return a < b }, nil, 1, dir+"/missing").
+		ToSliceErr()
+	assert.Error(t, err)
+	assert.Empty(t, got)
+
+	readErr := errors.New("read failed")
+	strs, err := FromReader(&failingReader{r: strings.NewReader("b\na\n"), err: readErr}, nil).
+		ExternalSort(func(a, b string) bool { return a < b }, nil, 1, dir).
+		ToSliceErr()
+	assert.ErrorIs(t, err, readErr)
+	assert.Empty(t, strs)
+	assertEmptyDir(t, dir)
+}
+
+func TestSliceOrderedExternalSort(t *testing.T) {
+	got := NewSliceByOrdered([]string{"c", "a", "b"}).
+		ExternalSort(func(a, b string) bool { return a > b }, nil, 1, t.TempDir()).
+		ToSlice()
+	assert.Equal(t, []string{"c", "b", "a"}, got)
+}
+
+func assertEmptyDir(t *testing.T, dir string) {
+	t.Helper()
+	entries, err := os.ReadDir(dir)
+	assert.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+type jsonCodec[E any] struct{}
+
+func (jsonCodec[E]) NewEncoder(w io.Writer) Encoder[E] {
+	return jsonEncoder[E]{enc: json.NewEncoder(w)}
+}
+
+func (jsonCodec[E]) NewDecoder(r io.Reader) Decoder[E] {
+	return jsonDecoder[E]{dec: json.NewDecoder(r)}
+}
+
+type jsonEncoder[E any] struct {
+	enc *json.Encoder
+}
+
+func (e jsonEncoder[E]) Encode(elem E) error {
+	return e.enc.Encode(elem)
+}
+
+type jsonDecoder[E any] struct {
+	dec *json.Decoder
+}
+
+func (d jsonDecoder[E]) Decode() (elem E, err error) {
+	err = d.dec.Decode(&elem)
+	return
+}
+
+type failingCodec[E any] struct{}
+
+func (failingCodec[E]) NewEncoder(io.Writer) Encoder[E] {
+	return failingCodec[E]{}
+}
+
+func (failingCodec[E]) NewDecoder(io.Reader) Decoder[E] {
+	return failingCodec[E]{}
+}
+
+func (failingCodec[E]) Encode(E) error {
+	return errors.New("encode failed")
+}
+
+func (failingCodec[E]) Decode() (e E, err error) {
+	return e, errors.New("decode failed")
+}
+
+// countingCodec jsonCodec counting the runs being read at once, max is the highest count.
+type countingCodec[E any] struct {
+	open int
+	max  int
+}
+
+func (c *countingCodec[E]) NewEncoder(w io.Writer) Encoder[E] {
+	return jsonCodec[E]{}.NewEncoder(w)
+}
+
+func (c *countingCodec[E]) NewDecoder(r io.Reader) Decoder[E] {
+	c.open++
+	if c.open > c.max {
+		c.max = c.open
+	}
+	return &countingDecoder[E]{codec: c, dec: jsonCodec[E]{}.NewDecoder(r)}
+}
+
+type countingDecoder[E any] struct {
+	codec *countingCodec[E]
+	dec   Decoder[E]
+	done  bool
+}
+
+func (d *countingDecoder[E]) Decode() (E, error) {
+	e, err := d.dec.Decode()
+	if err != nil && !d.done {
+		d.done = true
+		d.codec.open--
+	}
+	return e, err
+}
@@ -0,0 +1,179 @@
+package stream
+
+// FlatMap Returns a stream consisting of the elements of the slices returned by the mapper for each element of this stream.
+// An element can be mapped to zero or more elements, in their order.
+//
+// FlatMap is lazy, the elements are expanded as the stream is evaluated, so a following Limit stops the expansion.
+// The later stages index the expanded elements by their position in the resulting stream.
+//
+// Support Parallel, the original order is kept.
+func (stream SliceStream[E]) FlatMap(mapper func(E) []E) SliceStream[E] {
+	pipelineFlatMap(stream.Pipeline, stream.Pipeline, StageInfo{Name: "FlatMap"}, mapper)
+	return stream
+}
+
+// FlatMap Returns a stream consisting of the elements of the slices returned by the mapper for each element of the stream,
+// converting the type of elements.
+// See: SliceStream.FlatMap
+//
+// Support Parallel, the original order is kept.
+func FlatMap[E any, R any](stream SliceStream[E], mapper func(E) []R) SliceStream[R] {
+	dst := &Pipeline[R]{}
+	pipelineFlatMap(stream.Pipeline, dst, StageInfo{Name: "FlatMap"}, mapper)
+	return SliceStream[R]{Pipeline: dst}
+}
+
+// Flatten Returns a stream consisting of the elements of the slices of the stream, in their order.
+// See: SliceStream.FlatMap
+//
+// Support Parallel, the original order is kept.
+func Flatten[E any](stream SliceStream[[]E]) SliceStream[E] {
+	dst := &Pipeline[E]{}
+	pipelineFlatMap(stream.Pipeline, dst, StageInfo{Name: "Flatten"}, func(e []E) []E { return e })
+	return SliceStream[E]{Pipeline: dst}
+}
+
+// pipelineFlatMap Sets the source of the dst pipeline to the elements of the pipeline expanded by the mapper,
+// dst inherits the plan and the options of the pipeline, it may be the pipeline itself. See: pipelineGenerate
+func pipelineFlatMap[E any, R any](pipe *Pipeline[E], dst *Pipeline[R], info StageInfo, mapper func(E) []R) {
+	pipelineGenerate(pipe, dst, info, func(index int, e E, emit func(R) bool) bool {
+		for _, r := range mapper(e) {
+			if !emit(r) {
+				return false
+			}
+		}
+		return true
+	})
+}
+
+// pipelinePull Sets the source of the dst pipeline to the elements of the pipeline, pulled as they are evaluated,
+// used by the operations pulling the elements of their upstream such as Zip. See: pipelineGenerate
+func pipelinePull[E any](pipe *Pipeline[E], dst *Pipeline[E], info StageInfo) {
+	pipelineGenerate(pipe, dst, info, stageFlow(identity[E]))
+}
+
+// pipelineGenerate Sets the source of the dst pipeline to the results of last for the elements of the pipeline,
+// dst inherits the plan and the options of the pipeline, it may be the pipeline itself.
+// The source of dst is a generator evaluating the pending stages of the pipeline then last as its elements are pulled,
+// by one element at a time, or by batches processed by the goroutines of Parallel.
+// If the pipeline has a pending stateful push stage then it is evaluated first.
+func pipelineGenerate[E any, R any](pipe *Pipeline[E], dst *Pipeline[R], info StageInfo, last flow[E, R]) {
+	if stateful(pipe.nodes) < len(pipe.nodes) {
+		pipe.evaluation()
+	}
+	pipe.record(info)
+	if pipe.stages != nil {
+		last = chainFlow([]PushStage[E]{{Push: stageFlow(wrapTerminal(pipe.stages, identity[E]))}}, last)
+	}
+	up := &expansion[E, R]{
+		source:  pipe.source,
+		gen:     pipe.gen,
+		nodes:   pipe.nodes,
+		handler: chainFlow(pipe.nodes, last),
+	}
+	empty := pipe.source == nil && pipe.gen == nil
+	infinite := pipe.gen != nil && pipe.gen.infinite
+
+	inherit(dst, pipe)
+	dst.pending = pipe.pending
+	dst.source, dst.nodes, dst.stages, dst.gen, dst.shortCircuit = nil, nil, nil, nil, false
+	if empty {
+		return
+	}
+
+	// The options are read from dst when the stream is evaluated, so Parallel can be set after FlatMap.
+	up.down = dst
+	gen := pulled(infinite, up.next)
+	gen.close = func() {
+		if up.gen != nil && up.gen.close != nil {
+			up.gen.close()
+		}
+	}
+	up.err = &gen.err
+	dst.gen = gen
+}
+
+// expansion Evaluates the elements of an upstream pipeline by batches, as they are pulled.
+type expansion[E any, R any] struct {
+	source []E
+	gen    *generator[E]
+	down   *Pipeline[R]
+	// nodes The stateless push stages run by the handler, begun by the first expansion.
+	nodes   []PushStage[E]
+	handler flow[E, R]
+	// pos The index of the next upstream element.
+	pos int
+	// buffer The results not pulled yet.
+	buffer []R
+	done   bool
+	err    *error
+}
+
+// next Pulls the next result, processing upstream batches until one has at least one result.
+func (x *expansion[E, R]) next() (r R, ok bool) {
+	for len(x.buffer) == 0 {
+		if x.done {
+			return
+		}
+		x.expand()
+	}
+	r = x.buffer[0]
+	x.buffer = x.buffer[1:]
+	return r, true
+}
+
+// expand Processes the next upstream batch, one element if sequential.
+func (x *expansion[E, R]) expand() {
+	if x.pos == 0 {
+		for _, node := range x.nodes {
+			if node.Begin != nil {
+				node.Begin()
+			}
+		}
+	}
+	goroutines := x.down.parallelism()
+	n := 1
+	if goroutines > 1 {
+		n = goroutines * defaultBatchSize
+		if x.gen != nil && x.gen.batchSize > 0 {
+			n = goroutines * x.gen.batchSize
+		}
+	}
+
+	var batch []E
+	if x.gen != nil {
+		batch = pull(x.gen.next, n)
+		if x.gen.err != nil {
+			*x.err = x.gen.err
+		}
+	} else {
+		end := x.pos + n
+		if end > len(x.source) {
+			end = len(x.source)
+		}
+		batch = x.source[x.pos:end]
+	}
+	if len(batch) < n {
+		x.done = true
+	}
+
+	x.buffer = x.buffer[:0]
+	emit := func(r R) bool {
+		x.buffer = append(x.buffer, r)
+		return true
+	}
+	if goroutines > 1 {
+		p := Parallel[E, R]{goroutines: goroutines, slice: batch, handler: x.handler, offset: x.pos, executor: x.down.executor}
+		if !p.each(emit) {
+			x.done = true
+		}
+	} else {
+		for i, e := range batch {
+			if !x.handler(x.pos+i, e, emit) {
+				x.done = true
+				break
+			}
+		}
+	}
+	x.pos += len(batch)
+}
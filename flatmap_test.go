@@ -0,0 +1,134 @@
+package stream
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestSliceFlatMap(t *testing.T) {
+	tests := []struct {
+		name       string
+		input      []int
+		goroutines int
+		want       []int
+	}{
+		{
+			name:  "case",
+			input: []int{1, 2, 3},
+			want:  []int{2, 3, 3},
+		},
+		{
+			name:       "parallel",
+			input:      []int{1, 2, 3, 4, 5},
+			goroutines: 2,
+			want:       []int{2, 3, 3, 5, 5, 5, 5},
+		},
+		{
+			name:  "empty",
+			input: []int{},
+			want:  []int{},
+		},
+		{
+			name:  "nil",
+			input: nil,
+			want:  nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := NewSlice(tt.input).
+				Parallel(tt.goroutines).
+				Filter(func(v int) bool { return v != 4 }).
+				FlatMap(func(v int) []int {
+					ret := make([]int, 0, v)
+					for i := 1; i < v; i++ {
+						ret = append(ret, v)
+					}
+					return ret
+				}).
+				Filter(func(v int) bool { return v > 1 }).
+				ToSlice()
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestSliceFlatMapParallelOrder(t *testing.T) {
+	want := make([]int, 0, 30000)
+	for i := 0; i < 10000; i++ {
+		want = append(want, i, i, i)
+	}
+	for _, goroutines := range []int{0, 3, 8} {
+		got := Range(0, 10000, 1).
+			FlatMap(func(v int) []int { return []int{v, v, v} }).
+			Parallel(goroutines).
+			Map(func(v int) int { return v }).
+			ToSlice()
+		assert.Equal(t, want, got)
+	}
+}
+
+func TestSliceFlatMapLimit(t *testing.T) {
+	var expanded int64
+	got := NewSlice([]int{1, 2, 3, 4, 5}).
+		FlatMap(func(v int) []int {
+			atomic.AddInt64(&expanded, 1)
+			return []int{v, v * 10}
+		}).
+		Limit(3).
+		ToSlice()
+	assert.Equal(t, []int{1, 10, 2}, got)
+	assert.Equal(t, int64(2), expanded)
+
+	got = RangeFrom(1, 1).
+		Parallel(4).
+		FlatMap(func(v int) []int { return []int{v, -v} }).
+		Limit(4).
+		ToSlice()
+	assert.Equal(t, []int{1, -1, 2, -2}, got)
+
+	assert.Panics(t, func() {
+		RangeFrom(1, 1).FlatMap(func(v int) []int { return []int{v} }).ToSlice()
+	})
+}
+
+func TestFlatMap(t *testing.T) {
+	type order struct {
+		ID    int
+		Items []string
+	}
+	orders := []order{{1, []string{"a", "b"}}, {2, nil}, {3, []string{"c"}}}
+	for _, goroutines := range []int{0, 2} {
+		got := FlatMap(NewSlice(orders).Parallel(goroutines), func(o order) []string {
+			items := make([]string, 0, len(o.Items))
+			for _, item := range o.Items {
+				items = append(items, strconv.Itoa(o.ID)+item)
+			}
+			return items
+		}).Map(strings.ToUpper).ToSlice()
+		assert.Equal(t, []string{"1A", "1B", "3C"}, got)
+	}
+
+	s := NewSlice(orders).Filter(func(o order) bool { return o.ID > 1 }).Label("gt1")
+	items := FlatMap(s, func(o order) []string { return o.Items })
+	assert.Equal(t, "Pipeline (sequential)\n"+
+		"  1. Filter \"gt1\"\n"+
+		"  2. FlatMap\n", items.Explain())
+	assert.Equal(t, []string{"c"}, items.ToSlice())
+}
+
+func TestFlatten(t *testing.T) {
+	got := Flatten(NewSlice([][]int{{1, 2}, {}, nil, {3}})).Parallel(2).ToSlice()
+	assert.Equal(t, []int{1, 2, 3}, got)
+	assert.Nil(t, Flatten(NewSlice([][]int(nil))).ToSlice())
+
+	readErr := errors.New("read failed")
+	words, err := FlatMap(FromReader(&failingReader{r: strings.NewReader("a b\nc\n"), err: readErr}, nil), strings.Fields).
+		ToSliceErr()
+	assert.ErrorIs(t, err, readErr)
+	assert.Equal(t, []string{"a", "b", "c"}, words)
+}
@@ -0,0 +1,191 @@
+package stream
+
+import (
+	"math"
+
+	"golang.org/x/exp/constraints"
+)
+
+// defaultBatchSize The default number of elements pulled from a generator for each goroutine of Parallel.
+const defaultBatchSize = 256
+
+// Number Generics constraints based on numeric types.
+type Number interface {
+	constraints.Integer | constraints.Float
+}
+
+// generator Lazy source of a pipeline, the elements are generated as they are evaluated.
+type generator[E any] struct {
+	// size The number of elements, -1 if unknown until the source is exhausted.
+	size int
+	// infinite The source is never exhausted, only short-circuiting operations can evaluate it.
+	infinite bool
+	// next Pulls the next element in order, ok is false when the source is exhausted.
+	next func() (e E, ok bool)
+	// at Generates the element at the index, nil if the elements can only be pulled in order.
+	// Parallel uses it to generate the elements of each partition lazily.
+	at func(index int) E
+	// batchSize The number of elements pulled for each goroutine of Parallel, 0 means defaultBatchSize.
+	batchSize int
+	// err The error that stopped the generator, reported by Pipeline.Err.
+	err error
+	// close Releases the resources of the generator once it is evaluated, nil if there is none.
+	close func()
+}
+
+// indexed new generator of size elements generated by at, size < 0 means infinite.
+func indexed[E any](size int, at func(index int) E) *generator[E] {
+	i := 0
+	return &generator[E]{
+		size:     size,
+		infinite: size < 0,
+		at:       at,
+		next: func() (e E, ok bool) {
+			if size >= 0 && i >= size {
+				return
+			}
+			e = at(i)
+			i++
+			return e, true
+		},
+	}
+}
+
+// pulled new generator of the elements pulled by next.
+func pulled[E any](infinite bool, next func() (E, bool)) *generator[E] {
+	return &generator[E]{size: -1, infinite: infinite, next: next}
+}
+
+// newGenerated new stream instance over the generator.
+func newGenerated[E any](gen *generator[E]) SliceStream[E] {
+	return SliceStream[E]{Pipeline: &Pipeline[E]{gen: gen}}
+}
+
+// generatorEach Runs the parallel handler over the generator from the element at start, passing the results in order to emit
+// until the generator is exhausted, a stage completes the evaluation or emit returns false.
+// The elements before start have already been pulled.
+//
+// Sequential evaluation pulls one element at a time,
+// Parallel evaluation processes batches of elements, generated lazily by each partition if the generator supports at.
+func generatorEach[E any, R any](gen *generator[E], start int, p Parallel[E, R], emit func(R) bool) {
+	goroutines := p.goroutines
+	if goroutines <= 1 {
+		for i := start; ; i++ {
+			e, ok := gen.next()
+			if !ok {
+				return
+			}
+			if !p.handler(i, e, emit) {
+				return
+			}
+		}
+	}
+
+	batch := defaultBatchSize
+	if gen.batchSize > 0 {
+		batch = gen.batchSize
+	}
+	batch *= goroutines
+	for low := start; gen.size < 0 || low < gen.size; low += batch {
+		p.offset = low
+		if gen.at != nil {
+			p.at = gen.at
+			p.size = batch
+			if gen.size >= 0 && low+batch > gen.size {
+				p.size = gen.size - low
+			}
+		} else {
+			p.slice = pull(gen.next, batch)
+		}
+
+		if !p.each(emit) || p.len() < batch {
+			return
+		}
+	}
+}
+
+// pull Pulls at most n elements in order.
+func pull[E any](next func() (E, bool), n int) []E {
+	elems := make([]E, 0, n)
+	for len(elems) < n {
+		e, ok := next()
+		if !ok {
+			break
+		}
+		elems = append(elems, e)
+	}
+	return elems
+}
+
+// Range new stream instance of the numbers from start (inclusive) to end (exclusive) by step.
+// If step is negative then the numbers decrease, if step is 0 then Range panics.
+//
+// Support Parallel, the numbers of each partition are generated lazily.
+func Range[E Number](start, end, step E) SliceOrderedStream[E] {
+	if step == 0 {
+		panic("stream: Range step must not be zero")
+	}
+	size := int(math.Ceil((float64(end) - float64(start)) / float64(step)))
+	if size < 0 {
+		size = 0
+	}
+	stream := newGenerated(indexed(size, func(i int) E { return start + E(i)*step }))
+	return SliceOrderedStream[E]{SliceComparableStream: SliceComparableStream[E]{SliceStream: stream}}
+}
+
+// RangeFrom new infinite stream instance of the numbers from start by step.
+// It can only be evaluated by short-circuiting operations.
+//
+// Support Parallel, the numbers of each partition are generated lazily.
+func RangeFrom[E Number](start, step E) SliceOrderedStream[E] {
+	stream := newGenerated(indexed(-1, func(i int) E { return start + E(i)*step }))
+	return SliceOrderedStream[E]{SliceComparableStream: SliceComparableStream[E]{SliceStream: stream}}
+}
+
+// Iterate new infinite stream instance of seed, next(seed), next(next(seed)), ...
+// It can only be evaluated by short-circuiting operations.
+func Iterate[E any](seed E, next func(E) E) SliceStream[E] {
+	e, started := seed, false
+	return newGenerated(pulled(true, func() (E, bool) {
+		if started {
+			e = next(e)
+		}
+		started = true
+		return e, true
+	}))
+}
+
+// Generate new infinite stream instance of the elements returned by successive calls of the supplier.
+// It can only be evaluated by short-circuiting operations.
+func Generate[E any](supplier func() E) SliceStream[E] {
+	return newGenerated(pulled(true, func() (E, bool) {
+		return supplier(), true
+	}))
+}
+
+// Repeat new stream instance of the element repeated n times.
+// If n is negative then the stream is infinite, it can only be evaluated by short-circuiting operations.
+//
+// Support Parallel, the elements of each partition are generated lazily.
+func Repeat[E any](elem E, n int) SliceStream[E] {
+	return newGenerated(indexed(n, func(int) E { return elem }))
+}
+
+// Cycle new infinite stream instance repeating the elements of the source in order.
+// If the source is empty or nil then the stream is empty, otherwise it can only be evaluated by short-circuiting operations.
+//
+// Support Parallel, the elements of each partition are generated lazily.
+func Cycle[E any](source []E) SliceStream[E] {
+	if len(source) == 0 {
+		return newGenerated(indexed(0, func(int) (e E) { return }))
+	}
+	return newGenerated(indexed(-1, func(i int) E { return source[i%len(source)] }))
+}
+
+// FromChan new stream instance of the elements received from the channel, until the channel is closed.
+func FromChan[E any](source <-chan E) SliceStream[E] {
+	return newGenerated(pulled(false, func() (e E, ok bool) {
+		e, ok = <-source
+		return
+	}))
+}
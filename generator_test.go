@@ -0,0 +1,185 @@
+package stream
+
+import (
+	"github.com/stretchr/testify/assert"
+	"sync/atomic"
+	"testing"
+)
+
+func TestRange(t *testing.T) {
+	tests := []struct {
+		name  string
+		start int
+		end   int
+		step  int
+		want  []int
+	}{
+		{
+			name:  "case",
+			start: 0,
+			end:   5,
+			step:  1,
+			want:  []int{0, 1, 2, 3, 4},
+		},
+		{
+			name:  "case",
+			start: 1,
+			end:   10,
+			step:  3,
+			want:  []int{1, 4, 7},
+		},
+		{
+			name:  "case",
+			start: 5,
+			end:   0,
+			step:  -2,
+			want:  []int{5, 3, 1},
+		},
+		{
+			name:  "empty",
+			start: 5,
+			end:   0,
+			step:  1,
+			want:  []int{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Range(tt.start, tt.end, tt.step).ToSlice()
+			assert.Equal(t, tt.want, got)
+
+			got = Range(tt.start, tt.end, tt.step).Parallel(2).ToSlice()
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	assert.Equal(t, []float64{0, 0.5, 1, 1.5}, Range(0, 2, 0.5).ToSlice())
+	assert.Panics(t, func() { Range(0, 1, 0) })
+}
+
+func TestRangeParallel(t *testing.T) {
+	tests := []struct {
+		name       string
+		end        int
+		goroutines int
+	}{
+		{
+			name:       "case",
+			end:        100,
+			goroutines: 4,
+		},
+		{
+			name:       "case",
+			end:        10000,
+			goroutines: 7,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			predicate := func(v int) bool { return v%3 == 0 }
+			mapper := func(v int) int { return v * 2 }
+			assert.Equal(t,
+				Range(0, tt.end, 1).Filter(predicate).Map(mapper).ToSlice(),
+				Range(0, tt.end, 1).Parallel(tt.goroutines).Filter(predicate).Map(mapper).ToSlice())
+
+			var count int64
+			Range(0, tt.end, 1).Parallel(tt.goroutines).ForEach(func(int, int) { atomic.AddInt64(&count, 1) })
+			assert.Equal(t, int64(tt.end), count)
+
+			max, ok := Range(0, tt.end, 1).Parallel(tt.goroutines).Max()
+			assert.True(t, ok)
+			assert.Equal(t, tt.end-1, max)
+		})
+	}
+}
+
+func TestInfiniteGenerators(t *testing.T) {
+	tests := []struct {
+		name       string
+		goroutines int
+	}{
+		{
+			name:       "case",
+			goroutines: 0,
+		},
+		{
+			name:       "case",
+			goroutines: 4,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := RangeFrom(10, 10).Parallel(tt.goroutines).Filter(func(v int) bool { return v%20 == 0 }).Limit(3).ToSlice()
+			assert.Equal(t, []int{20, 40, 60}, got)
+
+			got = RangeFrom(0, 1).Parallel(tt.goroutines).TakeWhile(func(v int) bool { return v < 5 }).ToSlice()
+			assert.Equal(t, []int{0, 1, 2, 3, 4}, got)
+
+			got = Iterate(1, func(v int) int { return v * 2 }).Parallel(tt.goroutines).Limit(5).ToSlice()
+			assert.Equal(t, []int{1, 2, 4, 8, 16}, got)
+
+			n := 0
+			got = Generate(func() int { n++; return n }).Parallel(tt.goroutines).Limit(3).ToSlice()
+			assert.Equal(t, []int{1, 2, 3}, got)
+
+			got = Repeat(7, -1).Parallel(tt.goroutines).Limit(2).ToSlice()
+			assert.Equal(t, []int{7, 7}, got)
+
+			got = Cycle([]int{1, 2, 3}).Parallel(tt.goroutines).Limit(7).ToSlice()
+			assert.Equal(t, []int{1, 2, 3, 1, 2, 3, 1}, got)
+
+			assert.True(t, RangeFrom(0, 1).Parallel(tt.goroutines).AnyMatch(func(v int) bool { return v == 5000 }))
+			assert.False(t, RangeFrom(0, 1).Parallel(tt.goroutines).AllMatch(func(v int) bool { return v < 3 }))
+			assert.Equal(t, 1, Cycle([]int{1, 2, 3}).FindFunc(func(v int) bool { return v == 2 }))
+
+			assert.Panics(t, func() { RangeFrom(0, 1).Parallel(tt.goroutines).ToSlice() })
+			assert.Panics(t, func() { Generate(func() int { return 1 }).Parallel(tt.goroutines).Count() })
+		})
+	}
+}
+
+func TestFiniteGenerators(t *testing.T) {
+	assert.Equal(t, []string{"a", "a", "a"}, Repeat("a", 3).ToSlice())
+	assert.Equal(t, []string{}, Repeat("a", 0).ToSlice())
+	assert.Equal(t, []int{}, Cycle([]int{}).ToSlice())
+	assert.Equal(t, []int{}, Cycle[int](nil).Parallel(2).ToSlice())
+	assert.Equal(t, 1000, Repeat(1, 1000).Parallel(3).Count())
+}
+
+func TestFromChan(t *testing.T) {
+	tests := []struct {
+		name       string
+		input      []int
+		goroutines int
+	}{
+		{
+			name:       "case",
+			input:      newArray(100),
+			goroutines: 0,
+		},
+		{
+			name:       "case",
+			input:      newArray(3000),
+			goroutines: 4,
+		},
+		{
+			name:       "empty",
+			input:      []int{},
+			goroutines: 4,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ch := make(chan int)
+			go func() {
+				defer close(ch)
+				for _, v := range tt.input {
+					ch <- v
+				}
+			}()
+			got := FromChan(ch).Parallel(tt.goroutines).Map(func(v int) int { return v + 1 }).ToSlice()
+			want := NewSlice(tt.input).Map(func(v int) int { return v + 1 }).ToSlice()
+			assert.Equal(t, want, got)
+		})
+	}
+}
@@ -0,0 +1,223 @@
+package stream
+
+import (
+	"hash/maphash"
+	"sync"
+
+	"golang.org/x/exp/slices"
+)
+
+// KeyedStream Stream of elements grouped by the key returned by the key func, See: KeyBy.
+// The elements of each key are processed in their order.
+//
+// With Parallel, each key is routed by its hash to a fixed worker, so the state of a key is never shared between goroutines
+// and its elements are processed in their order without locks.
+// The stages of the stream before KeyBy are run by the goroutines of Parallel as usual.
+type KeyedStream[E any, K comparable] struct {
+	stream SliceStream[E]
+	key    func(E) K
+}
+
+// KeyBy Returns a keyed stream of the elements of the stream, grouped by the key returned by the key func.
+func KeyBy[E any, K comparable](stream SliceStream[E], key func(E) K) KeyedStream[E, K] {
+	return KeyedStream[E, K]{stream: stream, key: key}
+}
+
+// Parallel See: SliceStream.Parallel
+// The goroutines are the number of workers the keys are routed to.
+func (stream KeyedStream[E, K]) Parallel(goroutines int) KeyedStream[E, K] {
+	stream.stream = stream.stream.Parallel(goroutines)
+	return stream
+}
+
+// ReduceByKey Returns a map stream of the reduction of the elements of each key,
+// the first element of a key is the initial value of its reduction.
+// The entries are in the order of the first element of their key.
+//
+// Support Parallel.
+func (stream KeyedStream[E, K]) ReduceByKey(reduce func(acc E, e E) E) MapStream[K, E] {
+	return foldByKey(stream, StageInfo{Name: "ReduceByKey"}, func(e E) E { return e }, reduce)
+}
+
+// AggregateByKey Returns a map stream of the aggregation of the elements of each key, starting from the value returned by zero.
+// zero is called once for each key, so an accumulator holding a reference such as a slice or a map is not shared by the keys.
+// The entries are in the order of the first element of their key.
+//
+// Support Parallel, zero is called by the worker of the key.
+func AggregateByKey[E any, K comparable, A any](stream KeyedStream[E, K], zero func() A, aggregate func(acc A, e E) A) MapStream[K, A] {
+	return foldByKey(stream, StageInfo{Name: "AggregateByKey"}, func(e E) A { return aggregate(zero(), e) }, aggregate)
+}
+
+// MapWithState Returns a stream of the results of the mapper for each element, in the original order.
+// The mapper is passed the state of the key of the element, the zero value of S for its first element,
+// and can update it for the next elements of the key, such as a running count or the last seen value.
+//
+// Support Parallel, the state of a key is only accessed by the worker of the key.
+func MapWithState[E any, K comparable, S any, R any](stream KeyedStream[E, K], mapper func(state *S, e E) R) SliceStream[R] {
+	pipe := stream.stream.Pipeline
+	var results [][]ranked[R]
+	n := keyedRun(pipe, StageInfo{Name: "MapWithState"}, stream.key, func(workers int) func(w int) func(index int, k K, e E) {
+		results = make([][]ranked[R], workers)
+		return func(w int) func(index int, k K, e E) {
+			states := map[K]*S{}
+			return func(index int, k K, e E) {
+				state, ok := states[k]
+				if !ok {
+					state = new(S)
+					states[k] = state
+				}
+				results[w] = append(results[w], ranked[R]{index: index, elem: mapper(state, e)})
+			}
+		}
+	})
+
+	dst := &Pipeline[R]{}
+	if n >= 0 {
+		dst.source = make([]R, n)
+		for _, rs := range results {
+			for _, r := range rs {
+				dst.source[r.index] = r.elem
+			}
+		}
+	}
+	inherit(dst, pipe)
+	return SliceStream[R]{Pipeline: dst}
+}
+
+// keyFold The fold of the elements of a key, first is the index of its first element.
+type keyFold[A any] struct {
+	first int
+	acc   A
+}
+
+// foldByKey Returns a map stream of the fold of the elements of each key, initialized by init with the first element of the key.
+func foldByKey[E any, K comparable, A any](stream KeyedStream[E, K], info StageInfo, init func(E) A, fold func(acc A, e E) A) MapStream[K, A] {
+	pipe := stream.stream.Pipeline
+	var folds []map[K]*keyFold[A]
+	n := keyedRun(pipe, info, stream.key, func(workers int) func(w int) func(index int, k K, e E) {
+		folds = make([]map[K]*keyFold[A], workers)
+		return func(w int) func(index int, k K, e E) {
+			folds[w] = map[K]*keyFold[A]{}
+			return func(index int, k K, e E) {
+				if f, ok := folds[w][k]; ok {
+					f.acc = fold(f.acc, e)
+					return
+				}
+				folds[w][k] = &keyFold[A]{first: index, acc: init(e)}
+			}
+		}
+	})
+
+	dst := &Pipeline[KV[K, A]]{}
+	if n >= 0 {
+		merged := make([]ranked[KV[K, A]], 0)
+		for _, m := range folds {
+			for k, f := range m {
+				merged = append(merged, ranked[KV[K, A]]{index: f.first, elem: KV[K, A]{Key: k, Value: f.acc}})
+			}
+		}
+		slices.SortFunc(merged, func(a, b ranked[KV[K, A]]) bool { return a.index < b.index })
+		dst.source = make([]KV[K, A], len(merged))
+		for i, r := range merged {
+			dst.source[i] = r.elem
+		}
+	}
+	inherit(dst, pipe)
+	return MapStream[K, A]{Pipeline: dst}
+}
+
+// keyedElem Element routed to the worker of its key.
+type keyedElem[E any, K comparable] struct {
+	index int
+	key   K
+	elem  E
+}
+
+// keyedRun Evaluates the pipeline, routing each element by the hash of its key to a worker. See: hashKey
+// newWorkers is called with the number of workers, then the worker w is created by the returned func,
+// and is called by a single goroutine with the elements of its keys in their order.
+// Returns the number of elements, -1 if the stream has no source.
+func keyedRun[E any, K comparable](pipe *Pipeline[E], info StageInfo, key func(E) K,
+	newWorkers func(workers int) func(w int) func(index int, k K, e E)) int {
+	info.Evaluation = true
+	info.Stateful = true
+	pipe.record(info)
+	if pipe.source == nil && pipe.gen == nil {
+		return -1
+	}
+	pipe.bounded()
+
+	workers := pipe.parallelism()
+	newWorker := newWorkers(workers)
+	n := 0
+	if workers == 1 {
+		worker := newWorker(0)
+		pipe.each(func(e E) bool {
+			worker(n, key(e), e)
+			n++
+			return true
+		})
+		return n
+	}
+
+	chs := make([]chan []keyedElem[E, K], workers)
+	panics := make(chan any, workers)
+	var wg sync.WaitGroup
+	for w := range chs {
+		w, ch := w, make(chan []keyedElem[E, K], workers)
+		chs[w] = ch
+		wg.Add(1)
+		// The workers wait for the routed elements, so they run in their own goroutines
+		// rather than by the executor, which may run a task in the routing goroutine.
+		go func() {
+			defer wg.Done()
+			defer func() {
+				if r := recover(); r != nil {
+					panics <- r
+					// The routing goes on until the end of the input, the remaining batches are discarded.
+					for range ch {
+					}
+				}
+			}()
+			worker := newWorker(w)
+			for batch := range ch {
+				for _, x := range batch {
+					worker(x.index, x.key, x.elem)
+				}
+			}
+		}()
+	}
+
+	batches := make([][]keyedElem[E, K], workers)
+	var h maphash.Hash
+	func() {
+		defer func() {
+			for w, ch := range chs {
+				if len(batches[w]) > 0 {
+					ch <- batches[w]
+				}
+				close(ch)
+			}
+			wg.Wait()
+		}()
+		pipe.each(func(e E) bool {
+			k := key(e)
+			w := hashKey(&h, k) % uint64(workers)
+			// The elements are routed to a worker by batches, so the channels are not used for every element.
+			batches[w] = append(batches[w], keyedElem[E, K]{index: n, key: k, elem: e})
+			if len(batches[w]) == chunkSize {
+				chs[w] <- batches[w]
+				batches[w] = nil
+			}
+			n++
+			return true
+		})
+	}()
+
+	select {
+	case r := <-panics:
+		panic(r)
+	default:
+	}
+	return n
+}
@@ -0,0 +1,132 @@
+package stream
+
+import (
+	"strconv"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type visit struct {
+	user string
+	page int
+}
+
+func visits(n int) []visit {
+	vs := make([]visit, 0, n)
+	for i := 0; i < n; i++ {
+		vs = append(vs, visit{user: "u" + strconv.Itoa(i%7), page: i})
+	}
+	return vs
+}
+
+func visitUser(v visit) string { return v.user }
+
+func TestReduceByKey(t *testing.T) {
+	for _, goroutines := range []int{0, 4, Auto} {
+		got := KeyBy(NewSlice(visits(1000)), visitUser).
+			Parallel(goroutines).
+			ReduceByKey(func(acc, v visit) visit { return visit{user: acc.user, page: acc.page + v.page} }).
+			ToMap()
+		assert.Len(t, got, 7)
+		sum := 0
+		for _, v := range got {
+			sum += v.page
+		}
+		assert.Equal(t, 999*1000/2, sum)
+	}
+
+	// The entries are in the order of the first element of their key.
+	entries := KeyBy(NewSlice([]int{5, 2, 4, 1, 3}), func(v int) bool { return v%2 == 0 }).
+		Parallel(2).
+		ReduceByKey(func(acc, v int) int { return acc + v }).
+		Entries().
+		ToSlice()
+	assert.Equal(t, []KV[bool, int]{{Key: false, Value: 9}, {Key: true, Value: 6}}, entries)
+
+	assert.Empty(t, KeyBy(NewSlice([]int{}), func(v int) int { return v }).ReduceByKey(func(a, b int) int { return a }).ToMap())
+	assert.Nil(t, KeyBy(NewSlice[int](nil), func(v int) int { return v }).ReduceByKey(func(a, b int) int { return a }).ToMap())
+}
+
+func TestAggregateByKey(t *testing.T) {
+	for _, goroutines := range []int{0, 3} {
+		counts := AggregateByKey(KeyBy(NewSlice(visits(700)), visitUser).Parallel(goroutines), func() int { return 0 },
+			func(acc int, _ visit) int { return acc + 1 }).ToMap()
+		assert.Len(t, counts, 7)
+		for user, count := range counts {
+			assert.Equal(t, 100, count, user)
+		}
+
+		// The elements of a key are aggregated in their order.
+		pages := AggregateByKey(KeyBy(NewSlice(visits(70)), visitUser).Parallel(goroutines), func() string { return "" },
+			func(acc string, v visit) string { return acc + strconv.Itoa(v.page) + "," }).ToMap()
+		assert.Equal(t, "3,10,17,24,31,38,45,52,59,66,", pages["u3"])
+
+		// Each key has its own accumulator.
+		lists := AggregateByKey(KeyBy(NewSlice(visits(70)), visitUser).Parallel(goroutines), func() []int { return make([]int, 0, 16) },
+			func(acc []int, v visit) []int { return append(acc, v.page) }).ToMap()
+		assert.Equal(t, []int{3, 10, 17, 24, 31, 38, 45, 52, 59, 66}, lists["u3"])
+		assert.Equal(t, []int{4, 11, 18, 25, 32, 39, 46, 53, 60, 67}, lists["u4"])
+	}
+
+	// Upstream stages and generated sources.
+	got := AggregateByKey(KeyBy(Range(0, 100, 1).Filter(func(v int) bool { return v < 10 }).SliceStream,
+		func(v int) int { return v % 3 }).Parallel(4), func() int { return 0 }, func(acc, v int) int { return acc + v }).ToMap()
+	assert.Equal(t, map[int]int{0: 0 + 3 + 6 + 9, 1: 1 + 4 + 7, 2: 2 + 5 + 8}, got)
+
+	// Struct keys.
+	type cell struct{ row, col int }
+	cells := AggregateByKey(KeyBy(Range(0, 1000, 1).SliceStream, func(v int) cell { return cell{v % 2, v % 3} }).Parallel(4),
+		func() int { return 0 }, func(acc, _ int) int { return acc + 1 }).ToMap()
+	assert.Len(t, cells, 6)
+	assert.Equal(t, 167, cells[cell{0, 0}])
+}
+
+func TestMapWithState(t *testing.T) {
+	type seen struct {
+		count int
+		last  int
+	}
+	for _, goroutines := range []int{0, 4} {
+		got := MapWithState(KeyBy(NewSlice(visits(140)), visitUser).Parallel(goroutines),
+			func(state *seen, v visit) seen {
+				state.count++
+				prev := *state
+				state.last = v.page
+				return prev
+			}).ToSlice()
+		assert.Len(t, got, 140)
+		for i, s := range got {
+			assert.Equal(t, i/7+1, s.count)
+			if i >= 7 {
+				assert.Equal(t, i-7, s.last)
+			}
+		}
+	}
+
+	// Each key is processed by a single worker at a time.
+	var busy [7]int32
+	ok := int32(1)
+	_ = MapWithState(KeyBy(FromChan(sendAll(visits(7000))), visitUser).Parallel(8), func(_ *int, v visit) int {
+		i := v.page % 7
+		if atomic.AddInt32(&busy[i], 1) != 1 {
+			atomic.StoreInt32(&ok, 0)
+		}
+		atomic.AddInt32(&busy[i], -1)
+		return i
+	}).ToSlice()
+	assert.Equal(t, int32(1), ok)
+
+	assert.Panics(t, func() {
+		MapWithState(KeyBy(NewSlice(visits(1000)), visitUser).Parallel(4), func(_ *int, v visit) int {
+			if v.page == 500 {
+				panic("boom")
+			}
+			return v.page
+		})
+	})
+
+	plan := MapWithState(KeyBy(NewSlice([]int{1}), func(v int) int { return v }), func(_ *int, v int) int { return v }).Explain()
+	assert.Contains(t, plan, "MapWithState [evaluation, stateful]")
+}
@@ -0,0 +1,218 @@
+package stream
+
+import (
+	"golang.org/x/exp/constraints"
+	"golang.org/x/exp/slices"
+)
+
+// KV Key-value pair of a map entry.
+type KV[K comparable, V any] struct {
+	Key   K
+	Value V
+}
+
+// MapStream Stream over the entries of a map, generics constraints based on comparable keys.
+type MapStream[K comparable, V any] struct {
+	*Pipeline[KV[K, V]]
+	// less Orders the keys of a stream created by NewMapByOrdered, nil otherwise.
+	less func(a, b K) bool
+}
+
+// NewMap new stream instance over the entries of the map.
+// The entries are in the map iteration order, which is not specified.
+func NewMap[K comparable, V any](source map[K]V) MapStream[K, V] {
+	return MapStream[K, V]{Pipeline: &Pipeline[KV[K, V]]{source: entries(source)}}
+}
+
+// NewMapByOrdered new stream instance over the entries of the map.
+// The entries are sorted by key in ascending order, so the evaluation is deterministic.
+func NewMapByOrdered[K constraints.Ordered, V any](source map[K]V) MapStream[K, V] {
+	stream := NewMap(source)
+	stream.less = func(a, b K) bool { return a < b }
+	slices.SortFunc(stream.source, func(a, b KV[K, V]) bool { return stream.less(a.Key, b.Key) })
+	return stream
+}
+
+func entries[K comparable, V any](source map[K]V) []KV[K, V] {
+	if source == nil {
+		return nil
+	}
+	kvs := make([]KV[K, V], 0, len(source))
+	for k, v := range source {
+		kvs = append(kvs, KV[K, V]{Key: k, Value: v})
+	}
+	return kvs
+}
+
+// Parallel See: SliceStream.Parallel
+func (stream MapStream[K, V]) Parallel(goroutines int) MapStream[K, V] {
+	stream.goroutines = goroutines
+	return stream
+}
+
+// Count Returns the count of entries in this stream.
+func (stream MapStream[K, V]) Count() int {
+	stream.evaluation()
+	return len(stream.source)
+}
+
+// Entries Returns a stream of the entries of this stream, the pending operations are kept lazy.
+func (stream MapStream[K, V]) Entries() SliceStream[KV[K, V]] {
+	return SliceStream[KV[K, V]]{Pipeline: stream.Pipeline}
+}
+
+// FilterKeys Returns a stream consisting of the entries of this stream whose key match the given predicate.
+//
+// Support Parallel.
+func (stream MapStream[K, V]) FilterKeys(predicate func(K) bool) MapStream[K, V] {
+	stage := func(index int, e KV[K, V]) (isReturn bool, isComplete bool, ret KV[K, V]) {
+		return predicate(e.Key), false, e
+	}
+	stream.addStage(StageInfo{Name: "FilterKeys"}, stage)
+	return stream
+}
+
+// FilterValues Returns a stream consisting of the entries of this stream whose value match the given predicate.
+//
+// Support Parallel.
+func (stream MapStream[K, V]) FilterValues(predicate func(V) bool) MapStream[K, V] {
+	stage := func(index int, e KV[K, V]) (isReturn bool, isComplete bool, ret KV[K, V]) {
+		return predicate(e.Value), false, e
+	}
+	stream.addStage(StageInfo{Name: "FilterValues"}, stage)
+	return stream
+}
+
+// Keys Returns a stream consisting of the keys of this stream.
+//
+// Support Parallel.
+func (stream MapStream[K, V]) Keys() SliceStream[K] {
+	pipe := pipelineMap(stream.Pipeline, StageInfo{Name: "Keys"}, func(e KV[K, V]) K { return e.Key })
+	return SliceStream[K]{Pipeline: pipe}
+}
+
+// Label See: SliceStream.Label
+func (stream MapStream[K, V]) Label(label string) MapStream[K, V] {
+	stream.label(label)
+	return stream
+}
+
+// MapKeys Returns a stream consisting of the entries of this stream whose key is replaced by the result of the mapper.
+// If several entries are mapped to the same key then ToMap keeps the last one.
+//
+// Support Parallel.
+func (stream MapStream[K, V]) MapKeys(mapper func(K) K) MapStream[K, V] {
+	stage := func(index int, e KV[K, V]) (isReturn bool, isComplete bool, ret KV[K, V]) {
+		return true, false, KV[K, V]{Key: mapper(e.Key), Value: e.Value}
+	}
+	stream.addStage(StageInfo{Name: "MapKeys"}, stage)
+	return stream
+}
+
+// MapValues Returns a stream consisting of the entries of this stream whose value is replaced by the result of the mapper.
+//
+// Support Parallel.
+func (stream MapStream[K, V]) MapValues(mapper func(V) V) MapStream[K, V] {
+	stage := func(index int, e KV[K, V]) (isReturn bool, isComplete bool, ret KV[K, V]) {
+		return true, false, KV[K, V]{Key: e.Key, Value: mapper(e.Value)}
+	}
+	stream.addStage(StageInfo{Name: "MapValues"}, stage)
+	return stream
+}
+
+// MergeWith Returns a stream consisting of the entries of this stream merged with the entries of the other map.
+// If a key is present in both then the value is the result of the conflict function,
+// the entries whose key is only present in the other map are appended to the end of this stream,
+// sorted by key if the stream was created by NewMapByOrdered, otherwise in the map iteration order.
+func (stream MapStream[K, V]) MergeWith(other map[K]V, conflict func(key K, v1, v2 V) V) MapStream[K, V] {
+	stream.evaluationBy(StageInfo{Name: "MergeWith", Stateful: true})
+	if stream.source == nil && other == nil {
+		return stream
+	}
+
+	indexes := make(map[K]int, len(stream.source))
+	merged := make([]KV[K, V], 0, len(stream.source)+len(other))
+	for _, e := range stream.source {
+		if i, ok := indexes[e.Key]; ok {
+			merged[i] = e
+			continue
+		}
+		indexes[e.Key] = len(merged)
+		merged = append(merged, e)
+	}
+	for k, v := range other {
+		if i, ok := indexes[k]; ok {
+			merged[i].Value = conflict(k, merged[i].Value, v)
+			continue
+		}
+		merged = append(merged, KV[K, V]{Key: k, Value: v})
+	}
+	if appended := merged[len(indexes):]; stream.less != nil {
+		slices.SortFunc(appended, func(a, b KV[K, V]) bool { return stream.less(a.Key, b.Key) })
+	}
+	stream.source = merged
+	return stream
+}
+
+// Observe See: SliceStream.Observe
+func (stream MapStream[K, V]) Observe(observer Observer) MapStream[K, V] {
+	stream.observer = observer
+	return stream
+}
+
+// ToMap Returns a map of the entries in the stream.
+// If several entries have the same key then the last one is kept.
+func (stream MapStream[K, V]) ToMap() map[K]V {
+	stream.evaluation()
+	if stream.source == nil {
+		return nil
+	}
+	m := make(map[K]V, len(stream.source))
+	for _, e := range stream.source {
+		m[e.Key] = e.Value
+	}
+	return m
+}
+
+// Trace See: SliceStream.Trace
+func (stream MapStream[K, V]) Trace(tracer Tracer) MapStream[K, V] {
+	stream.tracer = tracer
+	return stream
+}
+
+// Values Returns a stream consisting of the values of this stream.
+//
+// Support Parallel.
+func (stream MapStream[K, V]) Values() SliceStream[V] {
+	pipe := pipelineMap(stream.Pipeline, StageInfo{Name: "Values"}, func(e KV[K, V]) V { return e.Value })
+	return SliceStream[V]{Pipeline: pipe}
+}
+
+// WithExecutor See: SliceStream.WithExecutor
+func (stream MapStream[K, V]) WithExecutor(executor Executor) MapStream[K, V] {
+	stream.executor = executor
+	return stream
+}
+
+// WithPartitioner See: SliceStream.WithPartitioner
+func (stream MapStream[K, V]) WithPartitioner(partitioner Partitioner[KV[K, V]]) MapStream[K, V] {
+	stream.partitioner = partitioner
+	return stream
+}
+
+// WithProfile See: SliceStream.WithProfile
+func (stream MapStream[K, V]) WithProfile(profile AutoProfile) MapStream[K, V] {
+	stream.profile = &profile
+	return stream
+}
+
+// Invert Returns a stream consisting of the entries of the stream with keys and values swapped.
+// If several entries have the same value then ToMap keeps the last one.
+//
+// Support Parallel.
+func Invert[K comparable, V comparable](stream MapStream[K, V]) MapStream[V, K] {
+	pipe := pipelineMap(stream.Pipeline, StageInfo{Name: "Invert"}, func(e KV[K, V]) KV[V, K] {
+		return KV[V, K]{Key: e.Value, Value: e.Key}
+	})
+	return MapStream[V, K]{Pipeline: pipe}
+}
@@ -0,0 +1,167 @@
+package stream
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestNewMapStream(t *testing.T) {
+	tests := []struct {
+		name  string
+		input map[string]int
+		want  map[string]int
+	}{
+		{
+			name:  "case",
+			input: map[string]int{"a": 1, "b": 2},
+			want:  map[string]int{"a": 1, "b": 2},
+		},
+		{
+			name:  "empty",
+			input: map[string]int{},
+			want:  map[string]int{},
+		},
+		{
+			name:  "nil",
+			input: nil,
+			want:  nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, NewMap(tt.input).ToMap())
+			assert.Equal(t, tt.want, NewMapByOrdered(tt.input).ToMap())
+			assert.Equal(t, len(tt.input), NewMap(tt.input).Count())
+		})
+	}
+}
+
+func TestMapStreamOrdered(t *testing.T) {
+	input := map[int]string{3: "c", 1: "a", 2: "b", 5: "e", 4: "d"}
+	got := NewMapByOrdered(input).Entries().ToSlice()
+	assert.Equal(t, []KV[int, string]{{1, "a"}, {2, "b"}, {3, "c"}, {4, "d"}, {5, "e"}}, got)
+
+	assert.Equal(t, []int{1, 2, 3, 4, 5}, NewMapByOrdered(input).Keys().ToSlice())
+	assert.Equal(t, []string{"a", "b", "c", "d", "e"}, NewMapByOrdered(input).Parallel(2).Values().ToSlice())
+}
+
+func TestMapStreamFilter(t *testing.T) {
+	tests := []struct {
+		name       string
+		input      map[string]int
+		goroutines int
+		want       map[string]int
+	}{
+		{
+			name:       "case",
+			input:      map[string]int{"a": 1, "b": 2, "c": 3, "dd": 4},
+			goroutines: 0,
+			want:       map[string]int{"c": 3},
+		},
+		{
+			name:       "case",
+			input:      map[string]int{"a": 1, "b": 2, "c": 3, "dd": 4},
+			goroutines: 3,
+			want:       map[string]int{"c": 3},
+		},
+		{
+			name:  "nil",
+			input: nil,
+			want:  nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := NewMap(tt.input).
+				Parallel(tt.goroutines).
+				FilterKeys(func(k string) bool { return len(k) == 1 }).
+				FilterValues(func(v int) bool { return v > 2 }).
+				ToMap()
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestMapStreamMap(t *testing.T) {
+	input := map[string]int{"a": 1, "b": 2, "c": 3}
+	got := NewMap(input).
+		MapKeys(func(k string) string { return "key_" + k }).
+		MapValues(func(v int) int { return v * 10 }).
+		ToMap()
+	assert.Equal(t, map[string]int{"key_a": 10, "key_b": 20, "key_c": 30}, got)
+
+	got = NewMapByOrdered(input).
+		Parallel(2).
+		MapKeys(func(k string) string { return "same" }).
+		ToMap()
+	assert.Equal(t, map[string]int{"same": 3}, got)
+}
+
+func TestMapStreamInvert(t *testing.T) {
+	input := map[string]int{"a": 1, "b": 2, "c": 2}
+	got := Invert(NewMapByOrdered(input)).ToMap()
+	assert.Equal(t, map[int]string{1: "a", 2: "c"}, got)
+
+	assert.Nil(t, Invert(NewMap[string, int](nil)).ToMap())
+}
+
+func TestMapStreamMergeWith(t *testing.T) {
+	tests := []struct {
+		name  string
+		input map[string]int
+		other map[string]int
+		want  map[string]int
+	}{
+		{
+			name:  "case",
+			input: map[string]int{"a": 1, "b": 2},
+			other: map[string]int{"b": 10, "c": 3},
+			want:  map[string]int{"a": 1, "b": 12, "c": 3},
+		},
+		{
+			name:  "empty",
+			input: map[string]int{},
+			other: map[string]int{"c": 3},
+			want:  map[string]int{"c": 3},
+		},
+		{
+			name:  "nil",
+			input: nil,
+			other: nil,
+			want:  nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := NewMap(tt.input).MergeWith(tt.other, func(k string, v1, v2 int) int { return v1 + v2 }).ToMap()
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestMapStreamMergeWithOrdered(t *testing.T) {
+	other := map[string]int{"b": 10, "e": 5, "c": 3, "d": 4, "f": 6}
+	for i := 0; i < 10; i++ {
+		got := NewMapByOrdered(map[string]int{"b": 2, "a": 1}).
+			MergeWith(other, func(k string, v1, v2 int) int { return v1 + v2 }).
+			Entries().
+			ToSlice()
+		assert.Equal(t, []KV[string, int]{{"a", 1}, {"b", 12}, {"c", 3}, {"d", 4}, {"e", 5}, {"f", 6}}, got)
+	}
+}
+
+func TestMapStreamEntries(t *testing.T) {
+	input := map[int]int{1: 1, 2: 2, 3: 3, 4: 4}
+	got := NewMapByOrdered(input).
+		FilterKeys(func(k int) bool { return k%2 == 0 }).
+		Entries().
+		Map(func(e KV[int, int]) KV[int, int] { return KV[int, int]{e.Key, e.Value * 2} }).
+		ToSlice()
+	assert.Equal(t, []KV[int, int]{{2, 4}, {4, 8}}, got)
+
+	s := NewMapByOrdered(input).
+		FilterValues(func(v int) bool { return v > 1 }).Label("gt1").
+		MapValues(func(v int) int { return v * 10 }).Values()
+	assert.Equal(t, []int{20, 30, 40}, s.ToSlice())
+	assert.Equal(t, "Pipeline (sequential)\n  1. FilterValues \"gt1\"\n  2. MapValues\n  3. Values [evaluation]\n", s.Explain())
+}
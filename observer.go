@@ -0,0 +1,272 @@
+package stream
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Observer Receives the metrics of the stages run by the stream evaluations.
+// The methods may be called concurrently by the goroutines of Parallel, implementations must be safe for concurrent use.
+type Observer interface {
+	// OnStageStart Called before an evaluation runs the stage.
+	OnStageStart(stage StageInfo)
+	// OnElement Called after the stage processed the element at index, passed is false if the stage dropped the element.
+	OnElement(stage StageInfo, index int, passed bool)
+	// OnStageEnd Called after an evaluation ran the stage.
+	OnStageEnd(stage StageInfo, stats StageStats)
+	// OnPartitionDone Called when a goroutine of Parallel has processed its partition.
+	OnPartitionDone(stats PartitionStats)
+}
+
+// StageStats Metrics of a stage during one evaluation.
+type StageStats struct {
+	// In The number of elements the stage received.
+	In int64
+	// Out The number of elements the stage passed to the next stage.
+	Out int64
+	// Dropped The number of elements the stage dropped.
+	Dropped int64
+	// Duration The time spent in the stage, summed over all goroutines.
+	Duration time.Duration
+}
+
+// PartitionStats Metrics of a partition processed by a goroutine of Parallel.
+type PartitionStats struct {
+	// Partition The index of the partition.
+	Partition int
+	// Low The first index of the partition, Includes index.
+	Low int
+	// High The last index of the partition, Excludes index.
+	High int
+	// Processed The number of elements processed before the partition completed or was canceled.
+	Processed int
+	// Duration The time spent processing the partition.
+	Duration time.Duration
+}
+
+// stageRecord Accumulates the metrics of a pending stage.
+type stageRecord struct {
+	id    int
+	in    int64
+	out   int64
+	nanos int64
+}
+
+func (rec *stageRecord) stats() StageStats {
+	in := atomic.LoadInt64(&rec.in)
+	out := atomic.LoadInt64(&rec.out)
+	dropped := in - out
+	if dropped < 0 {
+		// A push stage can emit more elements than it receives.
+		dropped = 0
+	}
+	return StageStats{
+		In:       in,
+		Out:      out,
+		Dropped:  dropped,
+		Duration: time.Duration(atomic.LoadInt64(&rec.nanos)),
+	}
+}
+
+// Observe Attaches the observer to the stream, the observer receives the metrics of every following evaluation.
+// Observe(nil) detaches the observer.
+func (stream SliceStream[E]) Observe(observer Observer) SliceStream[E] {
+	stream.observer = observer
+	return stream
+}
+
+// observeStage Wraps the stage to record its metrics while an observer or a tracer is attached.
+func (pipe *Pipeline[E]) observeStage(info StageInfo, stage Stage[E, E]) Stage[E, E] {
+	rec := &stageRecord{id: info.ID}
+	pipe.pending = append(pipe.pending, rec)
+	return func(index int, e E) (isReturn bool, isComplete bool, ret E) {
+		if pipe.observer == nil && pipe.tracer == nil {
+			return stage(index, e)
+		}
+		start := time.Now()
+		isReturn, isComplete, ret = stage(index, e)
+		atomic.AddInt64(&rec.nanos, int64(time.Since(start)))
+		atomic.AddInt64(&rec.in, 1)
+		if isReturn {
+			atomic.AddInt64(&rec.out, 1)
+		}
+		if pipe.observer != nil {
+			pipe.observer.OnElement(pipe.plan[rec.id], index, isReturn)
+		}
+		return
+	}
+}
+
+// observePush See: Pipeline.observeStage, the results of Push and Flush are counted as the output of the push stage.
+// The duration excludes the time spent by the next stages processing the results.
+func (pipe *Pipeline[E]) observePush(info StageInfo, stage PushStage[E]) PushStage[E] {
+	rec := &stageRecord{id: info.ID}
+	pipe.pending = append(pipe.pending, rec)
+	observed := func(emit func(E) bool, run func(emit func(E) bool) bool) (more bool, out int64) {
+		var next time.Duration
+		start := time.Now()
+		more = run(func(e E) bool {
+			out++
+			emitted := time.Now()
+			ok := emit(e)
+			next += time.Since(emitted)
+			return ok
+		})
+		atomic.AddInt64(&rec.nanos, int64(time.Since(start)-next))
+		atomic.AddInt64(&rec.out, out)
+		return
+	}
+
+	push, flush := stage.Push, stage.Flush
+	stage.Push = func(index int, e E, emit func(E) bool) bool {
+		if pipe.observer == nil && pipe.tracer == nil {
+			return push(index, e, emit)
+		}
+		atomic.AddInt64(&rec.in, 1)
+		more, out := observed(emit, func(emit func(E) bool) bool { return push(index, e, emit) })
+		if pipe.observer != nil {
+			pipe.observer.OnElement(pipe.plan[rec.id], index, out > 0)
+		}
+		return more
+	}
+	if flush != nil {
+		stage.Flush = func(emit func(E) bool) bool {
+			if pipe.observer == nil && pipe.tracer == nil {
+				return flush(emit)
+			}
+			more, _ := observed(emit, flush)
+			return more
+		}
+	}
+	return stage
+}
+
+// instrument Notifies the observer and starts the spans of an evaluation of the pending stages.
+// The returned func ends them, r is the recovered panic and err the error of the evaluation, if any.
+func (pipe *Pipeline[E]) instrument() (context.Context, func(r any, err error)) {
+	ctx, span := startSpan(pipe.tracer, context.Background(), "stream.evaluation")
+	span.SetAttributes(
+		Attribute{Key: "stream.goroutines", Value: pipe.goroutines},
+		Attribute{Key: "stream.elements", Value: pipe.size()},
+	)
+
+	pending := pipe.pending
+	spans := make([]Span, len(pending))
+	for i, rec := range pending {
+		info := pipe.plan[rec.id]
+		if pipe.observer != nil {
+			pipe.observer.OnStageStart(info)
+		}
+		_, spans[i] = startSpan(pipe.tracer, ctx, "stream.stage")
+		spans[i].SetAttributes(
+			Attribute{Key: "stream.stage.id", Value: info.ID},
+			Attribute{Key: "stream.stage.name", Value: info.Name},
+			Attribute{Key: "stream.stage.label", Value: info.Label},
+		)
+	}
+
+	return ctx, func(r any, err error) {
+		for i, rec := range pending {
+			stats := rec.stats()
+			if pipe.observer != nil {
+				pipe.observer.OnStageEnd(pipe.plan[rec.id], stats)
+			}
+			spans[i].SetAttributes(
+				Attribute{Key: "stream.stage.in", Value: stats.In},
+				Attribute{Key: "stream.stage.out", Value: stats.Out},
+				Attribute{Key: "stream.stage.dropped", Value: stats.Dropped},
+				Attribute{Key: "stream.stage.duration", Value: stats.Duration},
+			)
+			spans[i].End()
+		}
+		pipe.pending = nil
+
+		if err != nil {
+			span.RecordError(err)
+		}
+		if r != nil {
+			span.RecordError(panicError(r))
+		}
+		span.End()
+	}
+}
+
+// StageMetrics Metrics of a stage accumulated by MetricsCollector.
+type StageMetrics struct {
+	Stage StageInfo
+	// Runs The number of evaluations that ran the stage.
+	Runs int
+	StageStats
+}
+
+// MetricsCollector In-memory Observer, accumulates the metrics of stages and partitions.
+type MetricsCollector struct {
+	mu         sync.Mutex
+	stages     []*StageMetrics
+	partitions []PartitionStats
+}
+
+// NewMetricsCollector new in-memory Observer.
+func NewMetricsCollector() *MetricsCollector {
+	return &MetricsCollector{}
+}
+
+// OnStageStart See: Observer.OnStageStart
+func (c *MetricsCollector) OnStageStart(StageInfo) {}
+
+// OnElement See: Observer.OnElement
+func (c *MetricsCollector) OnElement(StageInfo, int, bool) {}
+
+// OnStageEnd See: Observer.OnStageEnd
+func (c *MetricsCollector) OnStageEnd(stage StageInfo, stats StageStats) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, m := range c.stages {
+		if m.Stage == stage {
+			m.Runs++
+			m.In += stats.In
+			m.Out += stats.Out
+			m.Dropped += stats.Dropped
+			m.Duration += stats.Duration
+			return
+		}
+	}
+	c.stages = append(c.stages, &StageMetrics{Stage: stage, Runs: 1, StageStats: stats})
+}
+
+// OnPartitionDone See: Observer.OnPartitionDone
+func (c *MetricsCollector) OnPartitionDone(stats PartitionStats) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.partitions = append(c.partitions, stats)
+}
+
+// Stages Returns the metrics of the observed stages, in the order they were first run.
+func (c *MetricsCollector) Stages() []StageMetrics {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	stages := make([]StageMetrics, 0, len(c.stages))
+	for _, m := range c.stages {
+		stages = append(stages, *m)
+	}
+	return stages
+}
+
+// Partitions Returns the metrics of the observed partitions, in the order they were done.
+func (c *MetricsCollector) Partitions() []PartitionStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	partitions := make([]PartitionStats, len(c.partitions))
+	copy(partitions, c.partitions)
+	return partitions
+}
+
+// Reset Discards the collected metrics.
+func (c *MetricsCollector) Reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.stages = nil
+	c.partitions = nil
+}
@@ -0,0 +1,145 @@
+package stream
+
+import (
+	"github.com/stretchr/testify/assert"
+	"sync"
+	"testing"
+)
+
+type countingObserver struct {
+	mu       sync.Mutex
+	starts   int
+	ends     int
+	elements map[int]int
+	dropped  map[int]int
+}
+
+func (o *countingObserver) OnStageStart(StageInfo) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.starts++
+}
+
+func (o *countingObserver) OnElement(stage StageInfo, _ int, passed bool) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.elements[stage.ID]++
+	if !passed {
+		o.dropped[stage.ID]++
+	}
+}
+
+func (o *countingObserver) OnStageEnd(StageInfo, StageStats) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.ends++
+}
+
+func (o *countingObserver) OnPartitionDone(PartitionStats) {}
+
+func TestObserver(t *testing.T) {
+	tests := []struct {
+		name       string
+		input      []int
+		goroutines int
+	}{
+		{
+			name:       "case",
+			input:      []int{1, 2, 3, 4, 5, 6},
+			goroutines: 0,
+		},
+		{
+			name:       "case",
+			input:      []int{1, 2, 3, 4, 5, 6},
+			goroutines: 3,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := &countingObserver{elements: map[int]int{}, dropped: map[int]int{}}
+			got := NewSlice(tt.input).
+				Parallel(tt.goroutines).
+				Observe(o).
+				Filter(func(v int) bool { return v%2 == 0 }).
+				Map(func(v int) int { return v * 10 }).
+				ToSlice()
+			assert.Equal(t, []int{20, 40, 60}, got)
+			assert.Equal(t, 2, o.starts)
+			assert.Equal(t, 2, o.ends)
+			assert.Equal(t, map[int]int{0: 6, 1: 3}, o.elements)
+			assert.Equal(t, map[int]int{0: 3}, o.dropped)
+		})
+	}
+}
+
+func TestMetricsCollector(t *testing.T) {
+	tests := []struct {
+		name       string
+		input      []int
+		goroutines int
+		partitions int
+	}{
+		{
+			name:       "case",
+			input:      newArray(100),
+			goroutines: 0,
+			partitions: 0,
+		},
+		{
+			name:       "case",
+			input:      newArray(100),
+			goroutines: 4,
+			partitions: 4,
+		},
+		{
+			name:       "empty",
+			input:      []int{},
+			goroutines: 4,
+			partitions: 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewMetricsCollector()
+			NewSlice(tt.input).
+				Parallel(tt.goroutines).
+				Observe(c).
+				Filter(func(v int) bool { return v < 100 }).Label("lt100").
+				ForEach(func(int, int) {})
+
+			stages := c.Stages()
+			assert.Equal(t, 2, len(stages))
+			want := 0
+			for _, v := range tt.input {
+				if v < 100 {
+					want++
+				}
+			}
+
+			assert.Equal(t, "lt100", stages[0].Stage.Label)
+			assert.Equal(t, 1, stages[0].Runs)
+			assert.Equal(t, int64(len(tt.input)), stages[0].In)
+			assert.Equal(t, int64(want), stages[0].Out)
+			assert.Equal(t, int64(len(tt.input)-want), stages[0].Dropped)
+
+			assert.Equal(t, "ForEach", stages[1].Stage.Name)
+			assert.Equal(t, int64(want), stages[1].In)
+			assert.Equal(t, int64(0), stages[1].Dropped)
+
+			partitions := c.Partitions()
+			assert.Equal(t, tt.partitions, len(partitions))
+			processed := 0
+			for _, p := range partitions {
+				processed += p.Processed
+				assert.Equal(t, p.High-p.Low, p.Processed)
+			}
+			if tt.partitions > 0 {
+				assert.Equal(t, len(tt.input), processed)
+			}
+
+			c.Reset()
+			assert.Empty(t, c.Stages())
+			assert.Empty(t, c.Partitions())
+		})
+	}
+}
@@ -0,0 +1,162 @@
+package stream
+
+// Option Optional value, either Some value or None. Returned by the Opt variants of the terminal operations.
+type Option[E any] struct {
+	value E
+	ok    bool
+}
+
+// Some Returns an Option of the value.
+func Some[E any](value E) Option[E] {
+	return Option[E]{value: value, ok: true}
+}
+
+// None Returns an empty Option.
+func None[E any]() Option[E] {
+	return Option[E]{}
+}
+
+// OptionOf Returns an Option of the value if ok, else None, from the results of a function returning (E, bool).
+func OptionOf[E any](value E, ok bool) Option[E] {
+	if !ok {
+		return None[E]()
+	}
+	return Some(value)
+}
+
+// IsSome Returns whether the Option has a value.
+func (o Option[E]) IsSome() bool {
+	return o.ok
+}
+
+// IsNone Returns whether the Option is empty.
+func (o Option[E]) IsNone() bool {
+	return !o.ok
+}
+
+// Get Returns the value and whether the Option has a value.
+// If the Option is empty then E Type default value is returned. ok return false
+func (o Option[E]) Get() (value E, ok bool) {
+	return o.value, o.ok
+}
+
+// Unwrap Returns the value, panics if the Option is empty.
+func (o Option[E]) Unwrap() E {
+	if !o.ok {
+		panic("stream: unwrap of a None option")
+	}
+	return o.value
+}
+
+// OrElse Returns the value, or other if the Option is empty.
+func (o Option[E]) OrElse(other E) E {
+	if !o.ok {
+		return other
+	}
+	return o.value
+}
+
+// OrElseGet Returns the value, or the result of supplier if the Option is empty.
+func (o Option[E]) OrElseGet(supplier func() E) E {
+	if !o.ok {
+		return supplier()
+	}
+	return o.value
+}
+
+// Filter Returns the Option if it has a value matching the predicate, else None.
+func (o Option[E]) Filter(predicate func(E) bool) Option[E] {
+	if !o.ok || !predicate(o.value) {
+		return None[E]()
+	}
+	return o
+}
+
+// Map Returns an Option of the result of the mapper for the value, or None if the Option is empty.
+// See: MapOption to convert the type of the value.
+func (o Option[E]) Map(mapper func(E) E) Option[E] {
+	return MapOption(o, mapper)
+}
+
+// FlatMap Returns the Option returned by the mapper for the value, or None if the Option is empty.
+// See: FlatMapOption to convert the type of the value.
+func (o Option[E]) FlatMap(mapper func(E) Option[E]) Option[E] {
+	return FlatMapOption(o, mapper)
+}
+
+// MapOption Returns an Option of the result of the mapper for the value, or None if the Option is empty,
+// converting the type of the value.
+func MapOption[E any, R any](o Option[E], mapper func(E) R) Option[R] {
+	if !o.ok {
+		return None[R]()
+	}
+	return Some(mapper(o.value))
+}
+
+// FlatMapOption Returns the Option returned by the mapper for the value, or None if the Option is empty,
+// converting the type of the value.
+func FlatMapOption[E any, R any](o Option[E], mapper func(E) Option[R]) Option[R] {
+	if !o.ok {
+		return None[R]()
+	}
+	return mapper(o.value)
+}
+
+// AtOpt Returns an Option of the element at the given index. See: SliceStream.At
+func (stream SliceStream[E]) AtOpt(index int) Option[E] {
+	return OptionOf(stream.At(index))
+}
+
+// FirstOpt Returns an Option of the first element in the stream. See: SliceStream.First
+func (stream SliceStream[E]) FirstOpt() Option[E] {
+	return OptionOf(stream.First())
+}
+
+// FindFuncOpt Returns an Option of the index of the first element in the stream that matches the provided predicate.
+// See: SliceStream.FindFunc
+//
+// Support Parallel.
+// Parallel side effect is that the element found may not be the first to appear
+func (stream SliceStream[E]) FindFuncOpt(predicate func(E) bool) Option[int] {
+	index := stream.FindFunc(predicate)
+	return OptionOf(index, index >= 0)
+}
+
+// MaxFuncOpt Returns an Option of the maximum element of this stream. See: SliceStream.MaxFunc
+// - less: return a > b
+func (stream SliceStream[E]) MaxFuncOpt(less func(a, b E) bool) Option[E] {
+	return OptionOf(stream.MaxFunc(less))
+}
+
+// FindOpt Returns an Option of the index of the first element in the stream that matches the target element.
+// See: SliceComparableStream.Find
+func (stream SliceComparableStream[E]) FindOpt(dest E) Option[int] {
+	index := stream.Find(dest)
+	return OptionOf(index, index >= 0)
+}
+
+// MaxOpt Returns an Option of the maximum element of this stream. See: SliceOrderedStream.Max
+func (stream SliceOrderedStream[E]) MaxOpt() Option[E] {
+	return OptionOf(stream.Max())
+}
+
+// MinOpt Returns an Option of the minimum element of this stream. See: SliceOrderedStream.Min
+func (stream SliceOrderedStream[E]) MinOpt() Option[E] {
+	return OptionOf(stream.Min())
+}
+
+// MinFuncOpt Returns an Option of the minimum element of this stream. See: SliceOrderedStream.MinFunc
+// - less: return a < b
+func (stream SliceOrderedStream[E]) MinFuncOpt(less func(a, b E) bool) Option[E] {
+	return OptionOf(stream.MinFunc(less))
+}
+
+// NthElementOpt Returns an Option of the n-th least element of this stream (from 0). See: SliceOrderedStream.NthElement
+func (stream SliceOrderedStream[E]) NthElementOpt(n int) Option[E] {
+	return OptionOf(stream.NthElement(n))
+}
+
+// MedianOpt Returns an Option of the median element of this stream. See: SliceOrderedStream.Median
+func (stream SliceOrderedStream[E]) MedianOpt() Option[E] {
+	return OptionOf(stream.Median())
+}
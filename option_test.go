@@ -0,0 +1,65 @@
+package stream
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOption(t *testing.T) {
+	some := Some(2)
+	none := None[int]()
+	assert.True(t, some.IsSome())
+	assert.True(t, none.IsNone())
+	assert.Equal(t, none, OptionOf(5, false))
+	assert.Equal(t, some, OptionOf(2, true))
+
+	v, ok := some.Get()
+	assert.Equal(t, 2, v)
+	assert.True(t, ok)
+	_, ok = none.Get()
+	assert.False(t, ok)
+
+	assert.Equal(t, 2, some.Unwrap())
+	assert.Panics(t, func() { none.Unwrap() })
+	assert.Equal(t, 2, some.OrElse(7))
+	assert.Equal(t, 7, none.OrElse(7))
+	assert.Equal(t, 7, none.OrElseGet(func() int { return 7 }))
+
+	double := func(v int) int { return v * 2 }
+	half := func(v int) Option[int] { return OptionOf(v/2, v%2 == 0) }
+	assert.Equal(t, Some(4), some.Map(double))
+	assert.Equal(t, none, none.Map(double))
+	assert.Equal(t, Some(1), some.FlatMap(half))
+	assert.Equal(t, none, Some(3).FlatMap(half))
+	assert.Equal(t, none, some.Filter(func(v int) bool { return v > 2 }))
+	assert.Equal(t, Some("2"), MapOption(some, strconv.Itoa))
+	assert.Equal(t, None[string](), FlatMapOption(none, func(v int) Option[string] { return Some(strconv.Itoa(v)) }))
+}
+
+func TestOptTerminals(t *testing.T) {
+	s := []int{3, 1, 4, 1, 5}
+	assert.Equal(t, Some(4), NewSlice(s).AtOpt(-3))
+	assert.Equal(t, None[int](), NewSlice(s).AtOpt(5))
+	assert.Equal(t, Some(3), NewSlice(s).FirstOpt())
+	assert.Equal(t, None[int](), NewSlice[int](nil).FirstOpt())
+	assert.Equal(t, Some(2), NewSlice(s).Parallel(2).FindFuncOpt(func(v int) bool { return v > 3 && v < 5 }))
+	assert.Equal(t, None[int](), NewSlice(s).FindFuncOpt(func(v int) bool { return v > 5 }))
+	assert.Equal(t, Some(5), NewSlice(s).MaxFuncOpt(func(a, b int) bool { return a > b }))
+	assert.Equal(t, Some(1), NewSliceByComparable(s).FindOpt(1))
+	assert.Equal(t, None[int](), NewSliceByComparable(s).FindOpt(9))
+
+	ordered := NewSliceByOrdered(s)
+	assert.Equal(t, Some(5), ordered.MaxOpt())
+	assert.Equal(t, Some(1), ordered.MinOpt())
+	assert.Equal(t, Some(1), ordered.MinFuncOpt(func(a, b int) bool { return a < b }))
+	assert.Equal(t, Some(3), ordered.NthElementOpt(2))
+	assert.Equal(t, None[int](), ordered.NthElementOpt(5))
+	assert.Equal(t, Some(3), ordered.MedianOpt())
+	assert.Equal(t, None[int](), NewSliceByOrdered([]int{}).MaxOpt())
+
+	// The options chain without checking ok.
+	label := MapOption(ordered.Filter(func(v int) bool { return v > 3 }).MinOpt(), strconv.Itoa).OrElse("none")
+	assert.Equal(t, "4", label)
+}
@@ -1,69 +1,350 @@
 package stream
 
-import "context"
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+)
 
 type Parallel[E any, R any] struct {
 	goroutines int
 	slice      []E
-	handler    func(index int, elem E) (isReturn bool, isComplete bool, result R)
+	handler    func(index int, elem E, emit func(R) bool) (more bool)
+	observer   Observer
+	tracer     Tracer
+	executor   Executor
+	// partitioner Assigns the elements to the partitions, nil means the uniform ranges of partition.
+	partitioner Partitioner[E]
+	ctx         context.Context // parent of the partition spans
+	// backpressure Whether each partition waits for its results to be consumed once resultBuffer chunks are pending,
+	// otherwise it buffers all its results so the partitions run ahead of the consumer.
+	backpressure bool
+	// local Returns the emit function of a partition, receiving its results instead of the consumer,
+	// and the function called at the end of the partition. Optional.
+	local func() (emit func(R) bool, done func())
+	// partitionHandler Returns the handler of a partition, used instead of handler,
+	// and the function called at the end of the partition. Optional.
+	partitionHandler func() (handler flow[E, R], done func())
+
+	// offset The index in the stream of the first element, the source is evaluated by batches.
+	offset int
+	// at Returns the element at the index in the stream, used instead of slice by the sources generating elements.
+	at func(index int) E
+	// size The number of elements generated by at.
+	size int
+}
+
+// chunkSize The number of results a goroutine of Parallel sends at once, so results are consumed as they are produced.
+const chunkSize = 64
+
+// resultBuffer The number of chunks of results a goroutine of a bounded Parallel produces ahead of their consumption,
+// so a slow consumer holds the goroutines rather than the whole results in memory.
+const resultBuffer = 4
+
+// buffer Returns the capacity of the channel of the results of a partition of n elements.
+func (p Parallel[E, R]) buffer(n int) int {
+	if p.backpressure {
+		return resultBuffer
+	}
+	// Buffered to hold every chunk of the partition, so the goroutines never wait for the results to be consumed.
+	return n/chunkSize + 1
 }
 
+// Run Runs the handler over the partitions of the slice, a panic of the handler is re-panicked in the calling goroutine.
 func (p Parallel[E, R]) Run() []R {
-	partitions := partition(p.slice, p.goroutines)
+	results, _ := p.run()
+	return results
+}
+
+// run See: Parallel.Run, complete reports whether a handler has completed the evaluation.
+func (p Parallel[E, R]) run() (results []R, complete bool) {
+	results = make([]R, 0, p.len())
+	complete = !p.each(func(r R) bool {
+		results = append(results, r)
+		return true
+	})
+	return results, complete
+}
+
+// each Runs the handler over the partitions of the slice, passing the results in the original order to emit
+// as soon as they are produced, until emit returns false.
+// Returns false if emit returned false or a handler completed the evaluation.
+func (p Parallel[E, R]) each(emit func(R) bool) (more bool) {
+	if p.partitioner != nil {
+		return p.eachPartitioned(emit)
+	}
+	partitions := partition(p.len(), p.goroutines)
 	resultChs := make([]chan []R, len(partitions))
+	panics := make(chan any, len(partitions))
+	var completed int32
 
-	ctx, cancel := context.WithCancel(context.Background())
+	// consumer is done when the results are no longer consumed, ctx when the partitions stop visiting elements.
+	consumer, stop := context.WithCancel(context.Background())
+	defer stop()
+	ctx, cancel := context.WithCancel(consumer)
 	defer cancel()
 
 	for i, pa := range partitions {
-		resultChs[i] = make(chan []R)
-		go p.do(ctx, cancel, resultChs[i], pa)
+		resultChs[i] = make(chan []R, p.buffer(pa.high-pa.low))
+		i, pa := i, pa
+		execute(p.executor, func() {
+			partitionDo(p, consumer, ctx, cancel, resultChs[i], panics, &completed, i, pa.low, pa.high,
+				func(visit func(i int) bool) {
+					for j := pa.low; j < pa.high && visit(j); j++ {
+					}
+				},
+				func(_ int, r R) R { return r })
+		})
+	}
+
+	more = p.resulted(resultChs, emit)
+	stop()
+	select {
+	case r := <-panics:
+		panic(r)
+	default:
+	}
+	return more && atomic.LoadInt32(&completed) == 0
+}
+
+// eachPartitioned See: Parallel.each, the elements are assigned to the partitions by the partitioner,
+// the results of the partitions are merged by the index of their element.
+func (p Parallel[E, R]) eachPartitioned(emit func(R) bool) (more bool) {
+	elems := p.slice
+	if p.at != nil {
+		elems = make([]E, p.size)
+		for i := range elems {
+			elems[i] = p.at(p.offset + i)
+		}
+	}
+	if len(elems) == 0 {
+		return true
+	}
+	p.slice, p.at = elems, nil
+	partitions := p.partitioner.Partition(elems, p.goroutines)
+	checkPartitions(partitions, len(elems))
+	resultChs := make([]chan []ranked[R], len(partitions))
+	panics := make(chan any, len(partitions))
+	var completed int32
+
+	consumer, stop := context.WithCancel(context.Background())
+	defer stop()
+	ctx, cancel := context.WithCancel(consumer)
+	defer cancel()
+
+	for i, indexes := range partitions {
+		resultChs[i] = make(chan []ranked[R], p.buffer(len(indexes)))
+		if len(indexes) == 0 {
+			close(resultChs[i])
+			continue
+		}
+		i, indexes := i, indexes
+		execute(p.executor, func() {
+			partitionDo(p, consumer, ctx, cancel, resultChs[i], panics, &completed, i, indexes[0], indexes[len(indexes)-1]+1,
+				func(visit func(i int) bool) {
+					for _, j := range indexes {
+						if !visit(j) {
+							return
+						}
+					}
+				},
+				func(j int, r R) ranked[R] { return ranked[R]{index: j, elem: r} })
+		})
 	}
 
-	result := p.resulted(resultChs, len(p.slice))
-	return result
+	more = mergeResulted(resultChs, emit)
+	stop()
+	select {
+	case r := <-panics:
+		panic(r)
+	default:
+	}
+	return more && atomic.LoadInt32(&completed) == 0
 }
 
-func (p Parallel[E, R]) do(
+// checkPartitions Panics unless the partitions returned by a Partitioner assign each index of [0, n) to exactly one partition,
+// in ascending order within each partition.
+func checkPartitions(partitions [][]int, n int) {
+	assigned := make([]bool, n)
+	for i, indexes := range partitions {
+		for j, index := range indexes {
+			if index < 0 || index >= n {
+				panic(fmt.Sprintf("stream: the partitioner returned the index %d out of the range [0, %d)", index, n))
+			}
+			if assigned[index] {
+				panic(fmt.Sprintf("stream: the partitioner assigned the index %d more than once", index))
+			}
+			if j > 0 && index < indexes[j-1] {
+				panic(fmt.Sprintf("stream: the partitioner returned the indexes of partition %d out of ascending order", i))
+			}
+			assigned[index] = true
+		}
+	}
+	for index, ok := range assigned {
+		if !ok {
+			panic(fmt.Sprintf("stream: the partitioner did not assign the index %d to a partition", index))
+		}
+	}
+}
+
+func (p Parallel[E, R]) len() int {
+	if p.at != nil {
+		return p.size
+	}
+	return len(p.slice)
+}
+
+func (p Parallel[E, R]) elem(i int) E {
+	if p.at != nil {
+		return p.at(p.offset + i)
+	}
+	return p.slice[i]
+}
+
+// partitionDo Runs the handler over the elements of the partition visited by each, low and high bound their indexes.
+// The results are converted by chunk with the index of their element and sent by chunks to resultCh,
+// until the consumer is done, then the partition stops without waiting for its results to be consumed.
+// ctx is done when the partitions stop visiting the elements, the results already produced are still sent.
+func partitionDo[E any, R any, C any](
+	p Parallel[E, R],
+	consumer context.Context,
 	ctx context.Context,
 	cancel context.CancelFunc,
-	resultCh chan []R,
-	pa part) {
+	resultCh chan []C,
+	panics chan any,
+	completed *int32,
+	index int,
+	low, high int,
+	each func(visit func(i int) bool),
+	chunk func(i int, r R) C) {
 
-	defer close(resultCh)
-	ret := make([]R, 0, pa.high-pa.low)
+	low, high = p.offset+low, p.offset+high
+	_, span := startSpan(p.tracer, p.ctx, "stream.partition")
+	span.SetAttributes(
+		Attribute{Key: "stream.partition", Value: index},
+		Attribute{Key: "stream.partition.low", Value: low},
+		Attribute{Key: "stream.partition.high", Value: high},
+	)
+	defer func() {
+		if r := recover(); r != nil {
+			span.RecordError(panicError(r))
+			panics <- r
+			cancel()
+		}
+		span.End()
+		close(resultCh)
+	}()
 
-	for i := pa.low; i < pa.high; i++ {
+	send := func(ret []C) bool {
+		select {
+		case resultCh <- ret:
+			return true
+		case <-consumer.Done():
+			return false
+		}
+	}
+	ret := make([]C, 0, chunkSize)
+	current := 0
+	emit := func(r R) bool {
+		ret = append(ret, chunk(current, r))
+		if len(ret) == chunkSize {
+			if !send(ret) {
+				return false
+			}
+			ret = make([]C, 0, chunkSize)
+		}
+		return true
+	}
+	if p.local != nil {
+		var done func()
+		emit, done = p.local()
+		defer done()
+	}
+	handler := p.handler
+	if p.partitionHandler != nil {
+		var done func()
+		handler, done = p.partitionHandler()
+		defer done()
+	}
+	start := time.Now()
+	processed := 0
+
+	each(func(i int) bool {
 		select {
 		case <-ctx.Done():
-			break
+			return false
 		default:
-			isReturn, isComplete, r := p.handler(i, p.slice[i])
-			if !isReturn {
-				continue
-			}
-			ret = append(ret, r)
-			if isComplete {
-				cancel()
-				break
+		}
+		processed++
+		current = i
+		if !handler(p.offset+i, p.elem(i), emit) {
+			if consumer.Err() == nil {
+				atomic.StoreInt32(completed, 1)
 			}
+			cancel()
+			return false
 		}
+		return true
+	})
+
+	span.SetAttributes(Attribute{Key: "stream.partition.processed", Value: processed})
+	if p.observer != nil {
+		p.observer.OnPartitionDone(PartitionStats{
+			Partition: index,
+			Low:       low,
+			High:      high,
+			Processed: processed,
+			Duration:  time.Since(start),
+		})
 	}
 
 	if len(ret) > 0 {
-		resultCh <- ret
+		send(ret)
 	}
-	return
 }
 
-func (p Parallel[E, R]) resulted(resultChs []chan []R, cap int) []R {
-	results := make([]R, 0, cap)
+// resulted Passes the results of the partitions in order to emit, until emit returns false.
+func (p Parallel[E, R]) resulted(resultChs []chan []R, emit func(R) bool) bool {
 	for _, resultCh := range resultChs {
 		for result := range resultCh {
-			results = append(results, result...)
+			for _, r := range result {
+				if !emit(r) {
+					return false
+				}
+			}
 		}
 	}
-	return results
+	return true
+}
+
+// mergeResulted Passes the results of the partitions to emit in the order of the index of their element,
+// the results of each partition are in that order, until emit returns false.
+func mergeResulted[R any](resultChs []chan []ranked[R], emit func(R) bool) bool {
+	heads := make([][]ranked[R], len(resultChs))
+	for {
+		next := -1
+		for i, resultCh := range resultChs {
+			if len(heads[i]) == 0 && resultCh != nil {
+				chunk, ok := <-resultCh
+				if !ok {
+					resultChs[i] = nil
+					continue
+				}
+				heads[i] = chunk
+			}
+			if len(heads[i]) > 0 && (next < 0 || heads[i][0].index < heads[next][0].index) {
+				next = i
+			}
+		}
+		if next < 0 {
+			return true
+		}
+		if !emit(heads[next][0].elem) {
+			return false
+		}
+		heads[next] = heads[next][1:]
+	}
 }
 
 // part  Uniform slices
@@ -73,9 +354,8 @@ type part struct {
 	high int //excludes index
 }
 
-// partition Given a specified source, evenly part according to the source.
-func partition[E any](slice []E, goroutines int) []part {
-	l := len(slice)
+// partition Given the length of a specified source, evenly part according to the source.
+func partition(l int, goroutines int) []part {
 	if l == 0 {
 		return nil
 	}
@@ -0,0 +1,119 @@
+package stream
+
+import "hash/maphash"
+
+// Partitioner Assigns the elements of a batch to the goroutines of Parallel.
+// The results are merged in the original order whatever the partitions.
+type Partitioner[E any] interface {
+	// Partition Returns the indexes of the elements of each partition, at most n partitions processed by their own goroutine.
+	// The indexes of a partition are in ascending order, each index of elems is in exactly one partition,
+	// otherwise Parallel panics.
+	Partition(elems []E, n int) [][]int
+}
+
+// PartitionerFunc Adapts a func to a Partitioner.
+type PartitionerFunc[E any] func(elems []E, n int) [][]int
+
+// Partition Returns f(elems, n).
+func (f PartitionerFunc[E]) Partition(elems []E, n int) [][]int {
+	return f(elems, n)
+}
+
+// RangePartitioner Returns the partitioner of contiguous ranges of equal size, the default of Parallel.
+func RangePartitioner[E any]() Partitioner[E] {
+	return PartitionerFunc[E](func(elems []E, n int) [][]int {
+		parts := partition(len(elems), n)
+		partitions := make([][]int, len(parts))
+		for i, pa := range parts {
+			partitions[i] = indexRange(pa.low, pa.high)
+		}
+		return partitions
+	})
+}
+
+// RoundRobinPartitioner Returns the partitioner assigning the elements to the partitions in turn,
+// so the elements whose cost grows with their index are spread over the goroutines.
+func RoundRobinPartitioner[E any]() Partitioner[E] {
+	return PartitionerFunc[E](func(elems []E, n int) [][]int {
+		if n > len(elems) {
+			n = len(elems)
+		}
+		partitions := make([][]int, n)
+		for i := range elems {
+			partitions[i%n] = append(partitions[i%n], i)
+		}
+		return partitions
+	})
+}
+
+// HashPartitioner Returns the partitioner assigning the elements by the hash of their key,
+// so the elements of a key are processed in their order by the same goroutine.
+func HashPartitioner[E any, K comparable](key func(E) K) Partitioner[E] {
+	seed := maphash.MakeSeed()
+	return PartitionerFunc[E](func(elems []E, n int) [][]int {
+		if n < 1 {
+			n = 1
+		}
+		var h maphash.Hash
+		h.SetSeed(seed)
+		partitions := make([][]int, n)
+		for i, e := range elems {
+			s := hashKey(&h, key(e)) % uint64(n)
+			partitions[s] = append(partitions[s], i)
+		}
+		return partitions
+	})
+}
+
+// WeightedPartitioner Returns the partitioner of contiguous ranges of about the same total cost,
+// the cost of each element is returned by the cost func, so skewed elements are balanced over the goroutines.
+// A negative cost counts as 0.
+func WeightedPartitioner[E any](cost func(E) float64) Partitioner[E] {
+	return PartitionerFunc[E](func(elems []E, n int) [][]int {
+		if n > len(elems) {
+			n = len(elems)
+		}
+		costs := make([]float64, len(elems))
+		total := 0.0
+		for i, e := range elems {
+			if c := cost(e); c > 0 {
+				costs[i] = c
+				total += c
+			}
+		}
+		if total == 0 {
+			return RangePartitioner[E]().Partition(elems, n)
+		}
+
+		partitions := make([][]int, 0, n)
+		low, sum := 0, 0.0
+		for i, c := range costs {
+			sum += c
+			// The range ends once its cost reaches its share, leaving at least an element for each remaining partition.
+			remaining := n - len(partitions) - 1
+			if remaining > 0 && (sum >= total*float64(len(partitions)+1)/float64(n) || len(elems)-i-1 == remaining) {
+				partitions = append(partitions, indexRange(low, i+1))
+				low = i + 1
+			}
+		}
+		return append(partitions, indexRange(low, len(elems)))
+	})
+}
+
+// indexRange Returns the indexes from low (inclusive) to high (exclusive).
+func indexRange(low, high int) []int {
+	indexes := make([]int, 0, high-low)
+	for i := low; i < high; i++ {
+		indexes = append(indexes, i)
+	}
+	return indexes
+}
+
+// WithPartitioner Sets the partitioner assigning the elements to the goroutines of Parallel,
+// contiguous ranges of equal size by default. See: Partitioner
+//
+// The partitioner only applies to the stages evaluated by this stream, a stream converting the type of elements uses the default.
+func (stream SliceStream[E]) WithPartitioner(partitioner Partitioner[E]) SliceStream[E] {
+	stream.partitioner = partitioner
+	return stream
+}
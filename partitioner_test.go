@@ -0,0 +1,158 @@
+package stream
+
+import (
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// assertPartitions Asserts the partitions are in ascending order and cover each index exactly once.
+func assertPartitions(t *testing.T, partitions [][]int, size int, n int) {
+	t.Helper()
+	assert.LessOrEqual(t, len(partitions), n)
+	var all []int
+	for _, indexes := range partitions {
+		assert.True(t, sort.IntsAreSorted(indexes))
+		all = append(all, indexes...)
+	}
+	sort.Ints(all)
+	assert.Equal(t, indexRange(0, size), all)
+}
+
+func TestPartitioners(t *testing.T) {
+	elems := []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+
+	ranges := RangePartitioner[int]().Partition(elems, 3)
+	assert.Equal(t, [][]int{{0, 1, 2}, {3, 4, 5}, {6, 7, 8, 9}}, ranges)
+	assert.Empty(t, RangePartitioner[int]().Partition(nil, 3))
+
+	rr := RoundRobinPartitioner[int]().Partition(elems, 3)
+	assert.Equal(t, [][]int{{0, 3, 6, 9}, {1, 4, 7}, {2, 5, 8}}, rr)
+	assertPartitions(t, RoundRobinPartitioner[int]().Partition(elems[:2], 4), 2, 4)
+
+	hashed := HashPartitioner(func(v int) int { return v % 2 }).Partition(elems, 4)
+	assertPartitions(t, hashed, len(elems), 4)
+	for _, indexes := range hashed {
+		for _, i := range indexes {
+			assert.Equal(t, elems[indexes[0]]%2, elems[i]%2)
+		}
+	}
+
+	// The last element costs as much as all the others.
+	weighted := WeightedPartitioner(func(v int) float64 {
+		if v == 10 {
+			return 45
+		}
+		return float64(v)
+	}).Partition(elems, 2)
+	assert.Equal(t, [][]int{indexRange(0, 9), {9}}, weighted)
+
+	// Each partition has at least an element.
+	weighted = WeightedPartitioner(func(v int) float64 {
+		if v == 1 {
+			return 100
+		}
+		return 0
+	}).Partition(elems, 4)
+	assertPartitions(t, weighted, len(elems), 4)
+	assert.Len(t, weighted, 4)
+	assert.Equal(t, RangePartitioner[int]().Partition(elems, 3),
+		WeightedPartitioner(func(int) float64 { return 0 }).Partition(elems, 3))
+}
+
+func TestParallelWithPartitioner(t *testing.T) {
+	source := make([]int, 1000)
+	expected := make([]int, 0, 1000)
+	for i := range source {
+		source[i] = i
+		if i%3 != 0 {
+			expected = append(expected, i*2)
+		}
+	}
+
+	partitioners := map[string]Partitioner[int]{
+		"range":       RangePartitioner[int](),
+		"round-robin": RoundRobinPartitioner[int](),
+		"hash":        HashPartitioner(func(v int) int { return v % 10 }),
+		"weighted":    WeightedPartitioner(func(v int) float64 { return float64(v) }),
+	}
+	for name, partitioner := range partitioners {
+		got := NewSlice(source).
+			WithPartitioner(partitioner).
+			Parallel(4).
+			Filter(func(v int) bool { return v%3 != 0 }).
+			Map(func(v int) int { return v * 2 }).
+			ToSlice()
+		assert.Equal(t, expected, got, name)
+
+		// Generated sources are partitioned by batches.
+		got = Range(0, 1000, 1).
+			WithPartitioner(partitioner).
+			Parallel(4).
+			Filter(func(v int) bool { return v%3 != 0 }).
+			Map(func(v int) int { return v * 2 }).
+			ToSlice()
+		assert.Equal(t, expected, got, name)
+
+		found := NewSlice(source).WithPartitioner(partitioner).Parallel(4).FindFunc(func(v int) bool { return v == 777 })
+		assert.Equal(t, 777, found, name)
+	}
+
+	// The elements of a key are processed in their order by the same goroutine.
+	var mu sync.Mutex
+	last := map[int]int{}
+	NewSlice(source).
+		WithPartitioner(HashPartitioner(func(v int) int { return v % 10 })).
+		Parallel(4).
+		ForEach(func(_ int, v int) {
+			mu.Lock()
+			defer mu.Unlock()
+			if prev, ok := last[v%10]; ok {
+				assert.Less(t, prev, v)
+			}
+			last[v%10] = v
+		})
+
+	// A custom partitioner.
+	var calls int
+	reversed := PartitionerFunc[int](func(elems []int, n int) [][]int {
+		calls++
+		return [][]int{indexRange(len(elems)/2, len(elems)), indexRange(0, len(elems)/2)}
+	})
+	got := NewSlice(source).WithPartitioner(reversed).Parallel(2).Map(func(v int) int { return v * 2 }).ToSlice()
+	assert.Len(t, got, 1000)
+	assert.Equal(t, 0, got[0])
+	assert.Equal(t, 1998, got[999])
+	assert.Equal(t, 1, calls)
+
+	// The partitions of a custom partitioner must cover the indexes exactly once, in ascending order.
+	invalid := map[string]PartitionerFunc[int]{
+		"stream: the partitioner did not assign the index 999 to a partition": func(elems []int, n int) [][]int {
+			return [][]int{indexRange(0, len(elems)-1)}
+		},
+		"stream: the partitioner assigned the index 0 more than once": func(elems []int, n int) [][]int {
+			return [][]int{indexRange(0, len(elems)), {0}}
+		},
+		"stream: the partitioner returned the index 1000 out of the range [0, 1000)": func(elems []int, n int) [][]int {
+			return [][]int{indexRange(0, len(elems)+1)}
+		},
+		"stream: the partitioner returned the indexes of partition 1 out of ascending order": func(elems []int, n int) [][]int {
+			return [][]int{indexRange(0, 500), {501, 500}, indexRange(502, len(elems))}
+		},
+	}
+	for message, partitioner := range invalid {
+		assert.PanicsWithValue(t, message, func() {
+			NewSlice(source).WithPartitioner(partitioner).Parallel(2).Map(func(v int) int { return v }).ToSlice()
+		})
+	}
+
+	assert.Panics(t, func() {
+		NewSlice(source).WithPartitioner(RoundRobinPartitioner[int]()).Parallel(4).ForEach(func(_ int, v int) {
+			if v == 500 {
+				panic("boom")
+			}
+		})
+	})
+}
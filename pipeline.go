@@ -1,14 +1,43 @@
 package stream
 
+import "context"
+
+// Stage Processes the element at index of the stream.
+// - isReturn: whether ret is a result of the stage
+// - isComplete: whether the evaluation completes after this element, even if ret is not returned
 type Stage[E any, R any] func(index int, e E) (isReturn bool, isComplete bool, ret R)
 
 type Pipeline[E any] struct {
 	source     []E
+	gen        *generator[E]
 	goroutines int
-	stages     Stage[E, E]
+	// nodes The push stages, run before stages.
+	nodes []PushStage[E]
+	// stages The stages added after the last push stage, fused into one.
+	stages Stage[E, E]
+	// shortCircuit Whether a push stage can complete the evaluation of an infinite source.
+	shortCircuit bool
+	plan         []StageInfo
+	pending      []*stageRecord
+	observer     Observer
+	tracer       Tracer
+	executor     Executor
+	profile      *AutoProfile
+	partitioner  Partitioner[E]
+	// checkpoint The checkpointing of the next evaluation, See: SliceStream.Checkpoint
+	checkpoint *checkpointConfig
+	// backpressure Whether the goroutines of Parallel wait for their results to be consumed, See: Pipeline.eachBounded
+	backpressure bool
+	err          error
 }
 
 func (pipe *Pipeline[E]) AddStage(s2 Stage[E, E]) {
+	pipe.addStage(StageInfo{Name: "Stage"}, s2)
+}
+
+func (pipe *Pipeline[E]) addStage(info StageInfo, s2 Stage[E, E]) {
+	info = pipe.record(info)
+	s2 = pipe.observeStage(info, s2)
 	if pipe.stages == nil {
 		pipe.stages = s2
 		return
@@ -24,12 +53,66 @@ func (pipe *Pipeline[E]) AddStage(s2 Stage[E, E]) {
 }
 
 func (pipe *Pipeline[E]) evaluation() {
-	if pipe.source == nil || pipe.stages == nil {
+	if pipe.gen != nil {
+		pipe.bounded()
+		pipe.source = pipelineRun(pipe, wrapTerminal(pipe.stages, identity[E]))
+		return
+	}
+	if pipe.source == nil || (pipe.stages == nil && pipe.nodes == nil) {
+		return
+	}
+	if pipe.stages == nil {
+		pipe.source = pipelineRun(pipe, identity[E])
 		return
 	}
 	pipe.source = pipelineRun(pipe, pipe.stages)
 }
 
+// bounded Panics if the source of the pipeline is infinite and no pending stage can complete the evaluation,
+// an infinite source can only be evaluated by short-circuiting operations.
+func (pipe *Pipeline[E]) bounded() {
+	if pipe.gen != nil && pipe.gen.infinite && !pipe.shortCircuit {
+		panic("stream: an infinite source can only be evaluated by short-circuiting operations " +
+			"(Limit, TakeWhile, FindFunc, AnyMatch, AllMatch)")
+	}
+}
+
+// Err Returns the error that stopped the source of the stream during the evaluations, if any.
+// The elements evaluated before the error are kept in the stream.
+func (pipe *Pipeline[E]) Err() error {
+	return pipe.err
+}
+
+// size Returns the number of elements of the source, -1 if unknown.
+func (pipe *Pipeline[E]) size() int {
+	if pipe.gen != nil {
+		return pipe.gen.size
+	}
+	return len(pipe.source)
+}
+
+// evaluationBy Records an operation that needs all the elements, then evaluates the pending stages.
+func (pipe *Pipeline[E]) evaluationBy(info StageInfo) {
+	info.Evaluation = true
+	pipe.record(info)
+	pipe.evaluation()
+}
+
+// record Appends the operation to the plan of the pipeline.
+func (pipe *Pipeline[E]) record(info StageInfo) StageInfo {
+	info.ID = len(pipe.plan)
+	pipe.plan = append(pipe.plan, info)
+	return info
+}
+
+// label Labels the last operation added to the pipeline.
+func (pipe *Pipeline[E]) label(label string) {
+	if len(pipe.plan) == 0 {
+		return
+	}
+	pipe.plan[len(pipe.plan)-1].Label = label
+}
+
 func (pipe *Pipeline[E]) evaluationBool(terminal Stage[E, bool]) *bool {
 	ret := pipelineRun(pipe, wrapTerminal(pipe.stages, terminal))
 	if len(ret) > 0 {
@@ -46,6 +129,11 @@ func (pipe *Pipeline[E]) evaluationInt(terminal Stage[E, int]) *int {
 	return nil
 }
 
+// identity Terminal stage returning the elements as they are.
+func identity[E any](index int, e E) (isReturn bool, isComplete bool, ret E) {
+	return true, false, e
+}
+
 func wrapTerminal[E any, R any](stage Stage[E, E], terminalStage Stage[E, R]) Stage[E, R] {
 	var stages Stage[E, R]
 	if stage == nil {
@@ -63,25 +151,258 @@ func wrapTerminal[E any, R any](stage Stage[E, E], terminalStage Stage[E, R]) St
 	return stages
 }
 
+// each Evaluates the pending stages, passing the results in order to emit until emit returns false.
+// Used by the terminal operations that consume the results incrementally.
+func (pipe *Pipeline[E]) each(emit func(E) bool) {
+	pipe.bounded()
+	pipelineEach(pipe, wrapTerminal(pipe.stages, identity[E]), emit)
+}
+
+// eachBounded See: Pipeline.each, the goroutines of Parallel produce a bounded number of results ahead of emit,
+// rather than buffering all their results. Used by the sinks consuming the results at their own pace.
+func (pipe *Pipeline[E]) eachBounded(emit func(E) bool) {
+	pipe.backpressure = true
+	defer func() { pipe.backpressure = false }()
+	pipe.each(emit)
+}
+
+// pipelineMap Evaluates the pipeline converting the elements with the mapper,
+// returns a new pipeline of the results that inherits the plan and the options of the pipeline.
+func pipelineMap[E any, R any](pipe *Pipeline[E], info StageInfo, mapper func(E) R) *Pipeline[R] {
+	return pipelineMapIndex(pipe, info, func(_ int, e E) R { return mapper(e) })
+}
+
+// pipelineMapIndex See: pipelineMap, the mapper is also passed the index of the element passed to the stages.
+func pipelineMapIndex[E any, R any](pipe *Pipeline[E], info StageInfo, mapper func(int, E) R) *Pipeline[R] {
+	info.Evaluation = true
+	pipe.record(info)
+	dst := &Pipeline[R]{}
+	if pipe.source != nil || pipe.gen != nil {
+		pipe.bounded()
+		terminal := func(index int, v E) (isReturn bool, isComplete bool, ret R) {
+			return true, false, mapper(index, v)
+		}
+		dst.source = pipelineRun(pipe, wrapTerminal(pipe.stages, terminal))
+	}
+	inherit(dst, pipe)
+	return dst
+}
+
 func pipelineRun[E any, R any](pipe *Pipeline[E], stages Stage[E, R]) []R {
+	results := make([]R, 0, len(pipe.source))
+	pipelineEach(pipe, stages, func(r R) bool {
+		results = append(results, r)
+		return true
+	})
+	return results
+}
+
+// pipelineEach Runs the push stages then the stages over the source, passing the results in the original order to emit
+// until the source is exhausted, a stage completes the evaluation or emit returns false.
+//
+// The stages before the first stateful push stage are run by the goroutines of Parallel,
+// the stateful push stages are run in order by the calling goroutine, then flushed. See: pipelineSequence
+func pipelineEach[E any, R any](pipe *Pipeline[E], stages Stage[E, R], emit func(R) bool) {
+	pipelineEachLocal(pipe, stages, emit, nil)
+}
+
+// pipelineEachLocal See: pipelineEach, if local is not nil then the results of each partition of Parallel
+// are passed to the emit function returned by local for the partition rather than to emit, in no particular order,
+// and done is called at the end of the partition. Used by the operations accumulating the results by partition.
+// The results of a sequential evaluation, or of the stages after a stateful push stage, are passed to emit.
+func pipelineEachLocal[E any, R any](pipe *Pipeline[E], stages Stage[E, R], emit func(R) bool, local func() (emit func(R) bool, done func())) {
+	ctx, done := pipe.instrument()
 	defer func() {
+		r := recover()
+		var err error
+		if pipe.gen != nil && pipe.gen.err != nil {
+			err = pipe.gen.err
+			pipe.err = err
+		}
+		if pipe.gen != nil && pipe.gen.close != nil {
+			pipe.gen.close()
+		}
+		done(r, err)
+		pipe.nodes = nil
 		pipe.stages = nil
+		pipe.shortCircuit = false
+		pipe.gen = nil
+		if r != nil {
+			panic(r)
+		}
 	}()
 
-	if pipe.goroutines > 1 {
-		return Parallel[E, R]{pipe.goroutines, pipe.source, stages}.Run()
+	nodes := pipe.nodes
+	for _, node := range nodes {
+		if node.Begin != nil {
+			node.Begin()
+		}
+	}
+	k := stateful(nodes)
+	if k == len(nodes) {
+		pipelineDrive(ctx, pipe, chainFlow(nodes, stageFlow(stages)), emit, local)
+		return
 	}
+	push, flush := pipelineSequence(ctx, pipe, nodes[k:], stageFlow(stages), emit)
+	pipelineDrive(ctx, pipe, chainFlow(nodes[:k], stageFlow(identity[E])), push, nil)
+	flush()
+}
 
-	results := make([]R, 0, len(pipe.source))
-	for i, v := range pipe.source {
-		isReturn, isComplete, ret := stages(i, v)
-		if !isReturn {
-			continue
+// pipelineSequence Returns the push function running the push stages from a stateful stage then last,
+// passing the results to emit, and the flush function flushing the stages in order at the end of the input.
+// The stateful stages are run sequentially. If the size of the source is known, the stateless stages after them
+// are run by the goroutines of Parallel over batches of their results, so Parallel still applies after Sort or Limit.
+// Otherwise they are run sequentially too, so the results of an unbounded source are emitted as they are evaluated.
+// See: sequenceFlow
+func pipelineSequence[E any, R any](ctx context.Context, pipe *Pipeline[E], nodes []PushStage[E], last flow[E, R], emit func(R) bool) (push func(E) bool, flush func()) {
+	if pipe.parallelism() <= 1 || pipe.size() < 0 {
+		return sequenceFlow(nodes, last, emit)
+	}
+	// The consecutive stateful stages are run together, then the stateless stages until the next stateful stage.
+	g := 1
+	for g < len(nodes) && nodes[g].Stateful {
+		g++
+	}
+	var (
+		batch      func(E) bool
+		flushBatch func() bool
+		flushNext  func()
+	)
+	if j := g + stateful(nodes[g:]); j == len(nodes) {
+		batch, flushBatch = batchFlow(ctx, pipe, chainFlow(nodes[g:], last), emit)
+	} else {
+		var next func(E) bool
+		next, flushNext = pipelineSequence(ctx, pipe, nodes[j:], last, emit)
+		batch, flushBatch = batchFlow(ctx, pipe, chainFlow(nodes[g:j], stageFlow(identity[E])), next)
+	}
+	push, flushGroup := sequenceFlow(nodes[:g], stageFlow(identity[E]), batch)
+	flush = func() {
+		flushGroup()
+		flushBatch()
+		if flushNext != nil {
+			flushNext()
+		}
+	}
+	return push, flush
+}
+
+// batchFlow Returns the push function collecting the elements into batches, each batch is run by the handler
+// over the goroutines of Parallel passing the results in order to emit, and the flush function running the last batch.
+// The elements are indexed by their position, both functions return false once the handler completed
+// the evaluation or emit returned false.
+func batchFlow[E any, R any](ctx context.Context, pipe *Pipeline[E], handler flow[E, R], emit func(R) bool) (push func(E) bool, flush func() bool) {
+	p := newParallel(ctx, pipe, handler)
+	p.goroutines = pipe.parallelism()
+	size := p.goroutines * defaultBatchSize
+	batch := make([]E, 0, size)
+	done := false
+	flush = func() bool {
+		if done || len(batch) == 0 {
+			return !done
 		}
-		results = append(results, ret)
-		if isComplete {
-			break
+		p.slice = batch
+		done = !p.each(emit)
+		p.offset += len(batch)
+		// The partitions stopped early may still read the batch, so it is not reused.
+		batch = make([]E, 0, size)
+		return !done
+	}
+	push = func(e E) bool {
+		if done {
+			return false
+		}
+		batch = append(batch, e)
+		if len(batch) < size {
+			return true
 		}
+		return flush()
 	}
-	return results
+	return push, flush
+}
+
+// pipelineDrive Runs the handler over the source of the pipeline, passing the results in the original order to emit
+// until the source is exhausted, the handler completes the evaluation or emit returns false.
+// With Parallel(Auto), the number of goroutines is picked after a sequential sampling of the first elements.
+// See: pipelineEachLocal for local.
+func pipelineDrive[E any, R any](ctx context.Context, pipe *Pipeline[E], handler flow[E, R], emit func(R) bool, local func() (func(R) bool, func())) {
+	var partitionHandler func() (flow[E, R], func())
+	if pipe.checkpoint != nil {
+		c, err := newCheckpointer(*pipe.checkpoint, pipe.size())
+		pipe.checkpoint = nil
+		if err != nil {
+			pipe.err = err
+			return
+		}
+		defer func() {
+			r := recover()
+			finished := r == nil && (pipe.gen == nil || pipe.gen.err == nil)
+			if err := c.finish(finished); err != nil && pipe.err == nil {
+				pipe.err = err
+			}
+			if r != nil {
+				panic(r)
+			}
+		}()
+		base := handler
+		handler = checkpointFlow(c, c.acquire(), base)
+		partitionHandler = func() (flow[E, R], func()) {
+			t := c.acquire()
+			return checkpointFlow(c, t, base), func() { c.release(t) }
+		}
+	}
+
+	goroutines, start := pipe.goroutines, 0
+	if goroutines == Auto {
+		var more bool
+		if goroutines, start, more = autoSample(pipe, handler, emit); !more {
+			return
+		}
+	}
+	p := newParallel(ctx, pipe, handler)
+	p.goroutines = goroutines
+	p.local = local
+	p.partitionHandler = partitionHandler
+
+	if pipe.gen != nil {
+		generatorEach(pipe.gen, start, p, emit)
+		return
+	}
+
+	if goroutines > 1 {
+		p.slice, p.offset = pipe.source[start:], start
+		p.each(emit)
+		return
+	}
+
+	for i := start; i < len(pipe.source); i++ {
+		if !handler(i, pipe.source[i], emit) {
+			return
+		}
+	}
+}
+
+func newParallel[E any, R any](ctx context.Context, pipe *Pipeline[E], handler flow[E, R]) Parallel[E, R] {
+	return Parallel[E, R]{
+		goroutines:   pipe.goroutines,
+		slice:        pipe.source,
+		handler:      handler,
+		observer:     pipe.observer,
+		tracer:       pipe.tracer,
+		executor:     pipe.executor,
+		partitioner:  pipe.partitioner,
+		backpressure: pipe.backpressure,
+		ctx:          ctx,
+	}
+}
+
+// inherit Copies the plan and the options of the src pipeline to the dst pipeline,
+// used by the operations that convert the type of elements.
+func inherit[E any, R any](dst *Pipeline[R], src *Pipeline[E]) {
+	dst.plan = src.Stages()
+	dst.goroutines = src.goroutines
+	dst.observer = src.observer
+	dst.tracer = src.tracer
+	dst.executor = src.executor
+	dst.profile = src.profile
+	dst.err = src.err
 }
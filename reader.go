@@ -0,0 +1,85 @@
+package stream
+
+import (
+	"bufio"
+	"io"
+)
+
+// SourceOption Configures a source reading from an io.Reader.
+type SourceOption func(*sourceOptions)
+
+type sourceOptions struct {
+	maxTokenSize int
+	batchSize    int
+	skipErrors   bool
+	onError      func(err *DecodeError)
+	header       bool
+}
+
+func newSourceOptions(opts []SourceOption) sourceOptions {
+	var o sourceOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// WithMaxTokenSize Sets the maximum size of a token, bufio.MaxScanTokenSize by default.
+// A longer token stops the source with bufio.ErrTooLong, reported by Pipeline.Err.
+func WithMaxTokenSize(size int) SourceOption {
+	return func(o *sourceOptions) {
+		o.maxTokenSize = size
+	}
+}
+
+// WithBatchSize Sets the number of elements read for each goroutine of Parallel before they are processed.
+func WithBatchSize(size int) SourceOption {
+	return func(o *sourceOptions) {
+		o.batchSize = size
+	}
+}
+
+// scanned new generator of the tokens scanned from the reader, converted by token.
+// The slice passed to token may be overwritten by the next scan.
+func scanned[E any](r io.Reader, split bufio.SplitFunc, o sourceOptions, token func([]byte) E) *generator[E] {
+	scanner := bufio.NewScanner(r)
+	if split != nil {
+		scanner.Split(split)
+	}
+	if o.maxTokenSize > 0 {
+		initial := bufio.MaxScanTokenSize
+		if o.maxTokenSize < initial {
+			initial = o.maxTokenSize
+		}
+		scanner.Buffer(make([]byte, 0, initial), o.maxTokenSize)
+	}
+
+	gen := pulled[E](false, nil)
+	gen.batchSize = o.batchSize
+	gen.next = func() (e E, ok bool) {
+		if !scanner.Scan() {
+			gen.err = scanner.Err()
+			return
+		}
+		return token(scanner.Bytes()), true
+	}
+	return gen
+}
+
+// FromReader new stream instance of the tokens read from the reader, split by the split function.
+// If split is nil then bufio.ScanLines is used, the reader is read lazily as the stream is evaluated.
+// A read error stops the source, it is reported by Pipeline.Err or ToSliceErr.
+//
+// Support Parallel, tokens are read by batches, and the original order is kept.
+func FromReader(r io.Reader, split bufio.SplitFunc, opts ...SourceOption) SliceStream[string] {
+	gen := scanned(r, split, newSourceOptions(opts), func(b []byte) string { return string(b) })
+	return newGenerated(gen)
+}
+
+// FromReaderBytes See: FromReader, the tokens are returned as byte slices.
+func FromReaderBytes(r io.Reader, split bufio.SplitFunc, opts ...SourceOption) SliceStream[[]byte] {
+	gen := scanned(r, split, newSourceOptions(opts), func(b []byte) []byte {
+		return append([]byte(nil), b...)
+	})
+	return newGenerated(gen)
+}
@@ -0,0 +1,111 @@
+package stream
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"strings"
+	"testing"
+)
+
+type failingReader struct {
+	r   io.Reader
+	err error
+}
+
+func (f *failingReader) Read(p []byte) (int, error) {
+	n, err := f.r.Read(p)
+	if err == io.EOF {
+		return n, f.err
+	}
+	return n, err
+}
+
+func TestFromReader(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		split bufio.SplitFunc
+		want  []string
+	}{
+		{
+			name:  "case",
+			input: "a\nb\nc\n",
+			want:  []string{"a", "b", "c"},
+		},
+		{
+			name:  "case",
+			input: "a b  c",
+			split: bufio.ScanWords,
+			want:  []string{"a", "b", "c"},
+		},
+		{
+			name:  "empty",
+			input: "",
+			want:  []string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := FromReader(strings.NewReader(tt.input), tt.split).ToSliceErr()
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+
+			got = FromReader(strings.NewReader(tt.input), tt.split).Parallel(2).ToSlice()
+			assert.Equal(t, tt.want, got)
+
+			bytes := FromReaderBytes(strings.NewReader(tt.input), tt.split).ToSlice()
+			assert.Equal(t, len(tt.want), len(bytes))
+			for i, b := range bytes {
+				assert.Equal(t, tt.want[i], string(b))
+			}
+		})
+	}
+}
+
+func TestFromReaderParallel(t *testing.T) {
+	var b strings.Builder
+	for i := 0; i < 10000; i++ {
+		fmt.Fprintf(&b, "line %d\n", i)
+	}
+	predicate := func(s string) bool { return !strings.HasSuffix(s, "7") }
+	mapper := strings.ToUpper
+
+	want := FromReader(strings.NewReader(b.String()), nil).Filter(predicate).Map(mapper).ToSlice()
+	assert.Equal(t, 9000, len(want))
+	assert.Equal(t, "LINE 0", want[0])
+
+	for _, goroutines := range []int{2, 4, 7} {
+		got := FromReader(strings.NewReader(b.String()), nil, WithBatchSize(10)).
+			Parallel(goroutines).
+			Filter(predicate).
+			Map(mapper).
+			ToSlice()
+		assert.Equal(t, want, got)
+	}
+}
+
+func TestFromReaderLazy(t *testing.T) {
+	r := strings.NewReader(strings.Repeat("line\n", 100000))
+	got := FromReader(r, nil).Limit(2).ToSlice()
+	assert.Equal(t, []string{"line", "line"}, got)
+	assert.True(t, r.Len() > 0)
+}
+
+func TestFromReaderErr(t *testing.T) {
+	readErr := errors.New("read failed")
+	got, err := FromReader(&failingReader{r: strings.NewReader("a\nb\n"), err: readErr}, nil).ToSliceErr()
+	assert.ErrorIs(t, err, readErr)
+	assert.Equal(t, []string{"a", "b"}, got)
+
+	s := FromReader(strings.NewReader("a\n"+strings.Repeat("x", 100)+"\nc\n"), nil, WithMaxTokenSize(10))
+	assert.Equal(t, 1, s.Count())
+	assert.ErrorIs(t, s.Err(), bufio.ErrTooLong)
+
+	tracer := NewRecordingTracer()
+	_, err = FromReader(&failingReader{r: strings.NewReader("a\n"), err: readErr}, nil).Trace(tracer).ToSliceErr()
+	assert.ErrorIs(t, err, readErr)
+	assert.ErrorIs(t, tracer.Spans()[0].Errors[0], readErr)
+}
@@ -0,0 +1,130 @@
+package stream
+
+// Result Value or error of an operation that may fail. See: TryMap, Partition
+type Result[E any] struct {
+	value E
+	err   error
+}
+
+// Ok Returns a successful Result of the value.
+func Ok[E any](value E) Result[E] {
+	return Result[E]{value: value}
+}
+
+// Fail Returns a failed Result of the error.
+func Fail[E any](err error) Result[E] {
+	return Result[E]{err: err}
+}
+
+// ResultOf Returns a Result of the value if err is nil, else of the error, from the results of a function returning (E, error).
+func ResultOf[E any](value E, err error) Result[E] {
+	if err != nil {
+		return Fail[E](err)
+	}
+	return Ok(value)
+}
+
+// IsOk Returns whether the Result is successful.
+func (r Result[E]) IsOk() bool {
+	return r.err == nil
+}
+
+// IsErr Returns whether the Result failed.
+func (r Result[E]) IsErr() bool {
+	return r.err != nil
+}
+
+// Get Returns the value and the error of the Result.
+// If the Result failed then E Type default value is returned.
+func (r Result[E]) Get() (E, error) {
+	return r.value, r.err
+}
+
+// Err Returns the error of the Result, nil if it is successful.
+func (r Result[E]) Err() error {
+	return r.err
+}
+
+// Unwrap Returns the value, panics with the error if the Result failed.
+func (r Result[E]) Unwrap() E {
+	if r.err != nil {
+		panic(r.err)
+	}
+	return r.value
+}
+
+// OrElse Returns the value, or other if the Result failed.
+func (r Result[E]) OrElse(other E) E {
+	if r.err != nil {
+		return other
+	}
+	return r.value
+}
+
+// Option Returns an Option of the value, None if the Result failed.
+func (r Result[E]) Option() Option[E] {
+	return OptionOf(r.value, r.err == nil)
+}
+
+// Map Returns a Result of the result of the mapper for the value, or the Result itself if it failed.
+// See: MapResult to convert the type of the value.
+func (r Result[E]) Map(mapper func(E) E) Result[E] {
+	return MapResult(r, mapper)
+}
+
+// FlatMap Returns the Result returned by the mapper for the value, or the Result itself if it failed.
+// See: FlatMapResult to convert the type of the value.
+func (r Result[E]) FlatMap(mapper func(E) Result[E]) Result[E] {
+	return FlatMapResult(r, mapper)
+}
+
+// MapResult Returns a Result of the result of the mapper for the value, or of the error if the Result failed,
+// converting the type of the value.
+func MapResult[E any, R any](r Result[E], mapper func(E) R) Result[R] {
+	if r.err != nil {
+		return Fail[R](r.err)
+	}
+	return Ok(mapper(r.value))
+}
+
+// FlatMapResult Returns the Result returned by the mapper for the value, or of the error if the Result failed,
+// converting the type of the value.
+func FlatMapResult[E any, R any](r Result[E], mapper func(E) Result[R]) Result[R] {
+	if r.err != nil {
+		return Fail[R](r.err)
+	}
+	return mapper(r.value)
+}
+
+// TryMap Returns a stream consisting of the Results of the mapper for each element of the stream,
+// an error of the mapper is kept in its Result rather than stopping the evaluation. See: Partition
+//
+// Support Parallel.
+func TryMap[E any, R any](stream SliceStream[E], mapper func(E) (R, error)) SliceStream[Result[R]] {
+	pipe := pipelineMap(stream.Pipeline, StageInfo{Name: "TryMap"}, func(e E) Result[R] {
+		return ResultOf(mapper(e))
+	})
+	return SliceStream[Result[R]]{Pipeline: pipe}
+}
+
+// Partition Evaluates the pending stages once, returns a stream of the values of the successful Results
+// and a stream of the errors of the failed Results, both in their order.
+// The streams inherit the plan and the options of the stream.
+func Partition[E any](stream SliceStream[Result[E]]) (SliceStream[E], SliceStream[error]) {
+	stream.record(StageInfo{Name: "Partition", Stateful: true, Evaluation: true})
+	oks, errs := &Pipeline[E]{}, &Pipeline[error]{}
+	if stream.source != nil || stream.gen != nil {
+		oks.source, errs.source = []E{}, []error{}
+		stream.each(func(r Result[E]) bool {
+			if r.err != nil {
+				errs.source = append(errs.source, r.err)
+			} else {
+				oks.source = append(oks.source, r.value)
+			}
+			return true
+		})
+	}
+	inherit(oks, stream.Pipeline)
+	inherit(errs, stream.Pipeline)
+	return SliceStream[E]{Pipeline: oks}, SliceStream[error]{Pipeline: errs}
+}
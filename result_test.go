@@ -0,0 +1,56 @@
+package stream
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResult(t *testing.T) {
+	errBoom := errors.New("boom")
+	ok := Ok(2)
+	failed := Fail[int](errBoom)
+	assert.True(t, ok.IsOk())
+	assert.True(t, failed.IsErr())
+	assert.Equal(t, ok, ResultOf(2, nil))
+	assert.Equal(t, failed, ResultOf(5, errBoom))
+
+	v, err := ok.Get()
+	assert.Equal(t, 2, v)
+	assert.NoError(t, err)
+	assert.ErrorIs(t, failed.Err(), errBoom)
+
+	assert.Equal(t, 2, ok.Unwrap())
+	assert.PanicsWithError(t, "boom", func() { failed.Unwrap() })
+	assert.Equal(t, 7, failed.OrElse(7))
+	assert.Equal(t, Some(2), ok.Option())
+	assert.Equal(t, None[int](), failed.Option())
+
+	double := func(v int) int { return v * 2 }
+	assert.Equal(t, Ok(4), ok.Map(double))
+	assert.Equal(t, failed, failed.Map(double))
+	assert.Equal(t, failed, ok.FlatMap(func(int) Result[int] { return failed }))
+	assert.Equal(t, Ok("2"), MapResult(ok, strconv.Itoa))
+	assert.Equal(t, Fail[string](errBoom), FlatMapResult(failed, func(v int) Result[string] { return Ok(strconv.Itoa(v)) }))
+}
+
+func TestPartition(t *testing.T) {
+	for _, goroutines := range []int{0, 3} {
+		results := TryMap(NewSlice([]string{"1", "x", "3", "y", "5"}).Parallel(goroutines), strconv.Atoi)
+		values, errs := Partition(results)
+		assert.Equal(t, []int{1, 3, 5}, values.ToSlice())
+		assert.Equal(t, 2, errs.Count())
+		assert.Contains(t, errs.ToSlice()[0].Error(), `"x"`)
+	}
+
+	values, errs := Partition(NewSlice([]Result[int]{Ok(1), Ok(2)}).Filter(func(r Result[int]) bool { return r.Unwrap() > 1 }))
+	assert.Equal(t, []int{2}, values.ToSlice())
+	assert.Equal(t, []error{}, errs.ToSlice())
+	assert.Contains(t, values.Map(func(v int) int { return v }).Explain(), "Partition [evaluation, stateful]")
+
+	values, errs = Partition(NewSlice[Result[int]](nil))
+	assert.Empty(t, values.ToSlice())
+	assert.Empty(t, errs.ToSlice())
+}
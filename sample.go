@@ -0,0 +1,93 @@
+package stream
+
+import (
+	"math/rand"
+	"time"
+
+	"golang.org/x/exp/slices"
+)
+
+// Sample Returns a stream consisting of k elements of this stream chosen uniformly at random, in their original order.
+// The elements are chosen by reservoir sampling in a single pass, so it supports the sources read lazily such as FromChan and FromReader.
+// If the stream has fewer than k elements then all of them are returned.
+//
+// The random numbers are pulled from src, or from a source seeded with the current time if src is nil.
+// The same seed gives the same sample regardless of the Parallel goroutines.
+//
+// Support Parallel.
+func (stream SliceStream[E]) Sample(k int, src rand.Source) SliceStream[E] {
+	stream.record(StageInfo{Name: "Sample", Stateful: true, Evaluation: true})
+	if stream.source == nil && stream.gen == nil {
+		return stream
+	}
+	if k < 0 {
+		k = 0
+	}
+
+	r := newRand(src)
+	reservoir := make([]ranked[E], 0, k)
+	n := 0
+	// The results are emitted in their original order, so the random numbers are pulled in a deterministic order.
+	stream.each(func(e E) bool {
+		if n < k {
+			reservoir = append(reservoir, ranked[E]{index: n, elem: e})
+		} else if j := r.Int63n(int64(n) + 1); j < int64(k) {
+			reservoir[j] = ranked[E]{index: n, elem: e}
+		}
+		n++
+		return true
+	})
+
+	slices.SortFunc(reservoir, func(a, b ranked[E]) bool { return a.index < b.index })
+	stream.source = make([]E, 0, len(reservoir))
+	for _, r := range reservoir {
+		stream.source = append(stream.source, r.elem)
+	}
+	return stream
+}
+
+// SampleFraction Returns a stream consisting of the elements of this stream each kept with the probability p (Bernoulli sampling).
+// Unlike Sample, SampleFraction is lazy and the number of kept elements is not fixed.
+//
+// The seed is pulled from src once, or from a source seeded with the current time if src is nil.
+// Whether an element is kept depends only on the seed and its index, so the same seed gives the same sample
+// regardless of the Parallel goroutines.
+//
+// Support Parallel.
+func (stream SliceStream[E]) SampleFraction(p float64, src rand.Source) SliceStream[E] {
+	seed := uint64(newRand(src).Int63())
+	stage := func(index int, e E) (isReturn bool, isComplete bool, ret E) {
+		return uniform(seed, uint64(index)) < p, false, e
+	}
+	stream.addStage(StageInfo{Name: "SampleFraction"}, stage)
+	return stream
+}
+
+// Shuffle Returns a stream consisting of the elements of this stream in a random order (Fisher-Yates shuffle).
+// The random numbers are pulled from src, or from a source seeded with the current time if src is nil.
+// The same seed gives the same order regardless of the Parallel goroutines.
+func (stream SliceStream[E]) Shuffle(src rand.Source) SliceStream[E] {
+	stream.evaluationBy(StageInfo{Name: "Shuffle", Stateful: true})
+	// The source may be the slice of the caller, so it is shuffled in a copy as Sort does.
+	stream.source = slices.Clone(stream.source)
+	r := newRand(src)
+	r.Shuffle(len(stream.source), func(i, j int) {
+		stream.source[i], stream.source[j] = stream.source[j], stream.source[i]
+	})
+	return stream
+}
+
+// newRand new rand.Rand pulling from src, or from a source seeded with the current time if src is nil.
+func newRand(src rand.Source) *rand.Rand {
+	if src == nil {
+		src = rand.NewSource(time.Now().UnixNano())
+	}
+	return rand.New(src)
+}
+
+// uniform Returns a number in [0, 1) derived from the seed and the index by splitmix64,
+// so the goroutines do not share a random source.
+func uniform(seed, index uint64) float64 {
+	x := mix64(seed + (index+1)*0x9e3779b97f4a7c15)
+	return float64(x>>11) / (1 << 53)
+}
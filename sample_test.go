@@ -0,0 +1,82 @@
+package stream
+
+import (
+	"github.com/stretchr/testify/assert"
+	"math/rand"
+	"strings"
+	"testing"
+
+	"golang.org/x/exp/slices"
+)
+
+func TestSliceSample(t *testing.T) {
+	input := Range(0, 1000, 1).ToSlice()
+	want := NewSlice(input).Sample(10, rand.NewSource(1)).ToSlice()
+	assert.Len(t, want, 10)
+	assert.True(t, slices.IsSorted(want))
+
+	for _, goroutines := range []int{2, 4, 7} {
+		got := NewSlice(input).Parallel(goroutines).Sample(10, rand.NewSource(1)).ToSlice()
+		assert.Equal(t, want, got)
+	}
+	assert.Equal(t, want, Range(0, 1000, 1).Parallel(3).Sample(10, rand.NewSource(1)).ToSlice())
+
+	assert.Equal(t, []int{1, 2}, NewSlice([]int{1, 2}).Sample(3, nil).ToSlice())
+	assert.Equal(t, []int{}, NewSlice([]int{1, 2}).Sample(0, nil).ToSlice())
+	assert.Nil(t, NewSlice([]int(nil)).Sample(3, nil).ToSlice())
+}
+
+func TestSliceSampleReader(t *testing.T) {
+	input := strings.Repeat("line\n", 100)
+	got, err := FromReader(strings.NewReader(input), nil).Sample(5, rand.NewSource(2)).ToSliceErr()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"line", "line", "line", "line", "line"}, got)
+}
+
+func TestSliceSampleFraction(t *testing.T) {
+	input := Range(0, 10000, 1).ToSlice()
+	want := NewSlice(input).SampleFraction(0.1, rand.NewSource(1)).ToSlice()
+	assert.InDelta(t, 1000, len(want), 150)
+	assert.True(t, slices.IsSorted(want))
+
+	for _, goroutines := range []int{2, 4, 7} {
+		got := NewSlice(input).Parallel(goroutines).SampleFraction(0.1, rand.NewSource(1)).ToSlice()
+		assert.Equal(t, want, got)
+	}
+	assert.Equal(t, want, Range(0, 10000, 1).Parallel(3).SampleFraction(0.1, rand.NewSource(1)).ToSlice())
+
+	assert.Equal(t, []int{}, NewSlice(input).SampleFraction(0, nil).ToSlice())
+	assert.Equal(t, input, NewSlice(input).SampleFraction(1, nil).ToSlice())
+	assert.Len(t, RangeFrom(0, 1).SampleFraction(0.5, nil).Limit(10).ToSlice(), 10)
+}
+
+func TestSliceShuffle(t *testing.T) {
+	input := Range(0, 100, 1).ToSlice()
+	want := NewSlice(slices.Clone(input)).Shuffle(rand.NewSource(1)).ToSlice()
+	assert.NotEqual(t, input, want)
+	assert.ElementsMatch(t, input, want)
+
+	for _, goroutines := range []int{2, 4} {
+		got := NewSlice(slices.Clone(input)).
+			Parallel(goroutines).
+			Map(func(v int) int { return v }).
+			Shuffle(rand.NewSource(1)).
+			ToSlice()
+		assert.Equal(t, want, got)
+	}
+	assert.Nil(t, NewSlice([]int(nil)).Shuffle(nil).ToSlice())
+
+	// The slice of the caller is not shuffled.
+	source := slices.Clone(input)
+	assert.Equal(t, want, NewSlice(source).Shuffle(rand.NewSource(1)).ToSlice())
+	assert.Equal(t, input, source)
+}
+
+func TestUniform(t *testing.T) {
+	for i := uint64(0); i < 1000; i++ {
+		u := uniform(42, i)
+		assert.True(t, u >= 0 && u < 1)
+	}
+	assert.Equal(t, uniform(1, 2), uniform(1, 2))
+	assert.NotEqual(t, uniform(1, 2), uniform(2, 2))
+}
@@ -0,0 +1,75 @@
+package stream
+
+import (
+	"context"
+	"io"
+)
+
+// ToChan Sends the elements of this stream to the channel as they are produced, then closes the channel.
+// It blocks until the channel receives every element, the whole result slice is never held in memory.
+//
+// Support Parallel, the elements are sent in the original order.
+func (stream SliceStream[E]) ToChan(ch chan<- E) {
+	defer close(ch)
+	stream.eachBounded(func(e E) bool {
+		ch <- e
+		return true
+	})
+}
+
+// ToChanContext See: SliceStream.ToChan, the evaluation stops when the context is done.
+// Returns the error of the context if the evaluation is stopped, or the error that stopped the source of the stream.
+//
+// Support Parallel, the elements are sent in the original order.
+func (stream SliceStream[E]) ToChanContext(ctx context.Context, ch chan<- E) error {
+	defer close(ch)
+	var err error
+	stream.eachBounded(func(e E) bool {
+		select {
+		case ch <- e:
+			return true
+		case <-ctx.Done():
+			err = ctx.Err()
+			return false
+		}
+	})
+	if err != nil {
+		return err
+	}
+	return stream.err
+}
+
+// WriteTo Writes the elements of this stream to w as they are produced, each converted to bytes by encode.
+// Returns the number of bytes written and the first write error, or the error that stopped the source of the stream.
+//
+// Support Parallel, the elements are written in the original order.
+func (stream SliceStream[E]) WriteTo(w io.Writer, encode func(E) []byte) (int64, error) {
+	var written int64
+	var err error
+	stream.eachBounded(func(e E) bool {
+		var n int
+		n, err = w.Write(encode(e))
+		written += int64(n)
+		return err == nil
+	})
+	if err != nil {
+		return written, err
+	}
+	return written, stream.err
+}
+
+// Drain Passes the elements of this stream to fn as they are produced, until fn returns an error.
+// Returns the first error of fn, or the error that stopped the source of the stream.
+//
+// Support Parallel, the elements are passed in the original order from the calling goroutine.
+func (stream SliceStream[E]) Drain(fn func(E) error) error {
+	var err error
+	stream.eachBounded(func(e E) bool {
+		err = fn(e)
+		return err == nil
+	})
+	if err != nil {
+		return err
+	}
+	return stream.err
+}
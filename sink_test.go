@@ -0,0 +1,170 @@
+package stream
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestSliceToChan(t *testing.T) {
+	tests := []struct {
+		name       string
+		input      []int
+		goroutines int
+		want       []int
+	}{
+		{
+			name:  "case",
+			input: []int{1, 2, 3, 4, 5},
+			want:  []int{2, 4},
+		},
+		{
+			name:       "parallel",
+			input:      Range(0, 1000, 1).ToSlice(),
+			goroutines: 4,
+			want:       Range(0, 1000, 2).ToSlice(),
+		},
+		{
+			name:  "nil",
+			input: nil,
+			want:  nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ch := make(chan int)
+			go NewSlice(tt.input).
+				Parallel(tt.goroutines).
+				Filter(func(v int) bool { return v%2 == 0 }).
+				ToChan(ch)
+			var got []int
+			for v := range ch {
+				got = append(got, v)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestSliceToChanBackpressure(t *testing.T) {
+	source := make([]int, 1000000)
+	for i := range source {
+		source[i] = i
+	}
+	// The goroutines wait for the results to be consumed rather than producing all of them,
+	// so they are never more than a few chunks ahead of the consumer.
+	const ahead = 4 * (resultBuffer + 3) * chunkSize
+	var produced, consumed, maxAhead int64
+	ch := make(chan int)
+	go NewSlice(source).
+		Parallel(4).
+		Peek(func(int, int) {
+			n := atomic.AddInt64(&produced, 1) - atomic.LoadInt64(&consumed)
+			for m := atomic.LoadInt64(&maxAhead); n > m && !atomic.CompareAndSwapInt64(&maxAhead, m, n); {
+				m = atomic.LoadInt64(&maxAhead)
+			}
+		}).
+		ToChan(ch)
+	n := 0
+	for v := range ch {
+		assert.Equal(t, n, v)
+		n++
+		atomic.AddInt64(&consumed, 1)
+	}
+	assert.Equal(t, len(source), n)
+	assert.LessOrEqual(t, atomic.LoadInt64(&maxAhead), int64(ahead))
+}
+
+func TestSliceToChanContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	ch := make(chan int)
+	errCh := make(chan error)
+	go func() {
+		errCh <- Range(0, 1<<20, 1).Parallel(4).ToChanContext(ctx, ch)
+	}()
+	assert.Equal(t, 0, <-ch)
+	assert.Equal(t, 1, <-ch)
+	cancel()
+	for range ch {
+	}
+	assert.ErrorIs(t, <-errCh, context.Canceled)
+}
+
+func TestSliceWriteTo(t *testing.T) {
+	tests := []struct {
+		name       string
+		input      []int
+		goroutines int
+		want       string
+	}{
+		{
+			name:  "case",
+			input: []int{1, 2, 3},
+			want:  "1\n2\n3\n",
+		},
+		{
+			name:       "parallel",
+			input:      []int{1, 2, 3, 4, 5, 6},
+			goroutines: 3,
+			want:       "1\n2\n3\n4\n5\n6\n",
+		},
+		{
+			name:  "nil",
+			input: nil,
+			want:  "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			n, err := NewSlice(tt.input).Parallel(tt.goroutines).WriteTo(&buf, func(v int) []byte {
+				return []byte(strconv.Itoa(v) + "\n")
+			})
+			assert.NoError(t, err)
+			assert.Equal(t, int64(len(tt.want)), n)
+			assert.Equal(t, tt.want, buf.String())
+		})
+	}
+
+	_, err := NewSlice([]int{1, 2}).WriteTo(failingWriter{}, func(v int) []byte { return []byte{byte(v)} })
+	assert.Error(t, err)
+
+	_, err = FromReader(&failingReader{r: strings.NewReader("a\n"), err: errors.New("read failed")}, nil).WriteTo(&bytes.Buffer{}, func(s string) []byte { return []byte(s) })
+	assert.Error(t, err)
+}
+
+func TestSliceDrain(t *testing.T) {
+	var got []string
+	err := NewSlice([]string{"a", "b", "c"}).
+		Parallel(2).
+		Map(strings.ToUpper).
+		Drain(func(s string) error {
+			got = append(got, s)
+			return nil
+		})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"A", "B", "C"}, got)
+
+	stop := errors.New("stop")
+	got = nil
+	err = Range(0, 1000, 1).Parallel(4).Drain(func(v int) error {
+		if v == 3 {
+			return stop
+		}
+		got = append(got, strconv.Itoa(v))
+		return nil
+	})
+	assert.ErrorIs(t, err, stop)
+	assert.Equal(t, []string{"0", "1", "2"}, got)
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) {
+	return 0, errors.New("write failed")
+}
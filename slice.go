@@ -15,6 +15,7 @@ func NewSlice[E any](source []E) SliceStream[E] {
 }
 
 // Parallel Goroutines > 1 enable parallel, Goroutines <= 1 disable parallel
+// Parallel(Auto) picks the number of goroutines of each evaluation, See: Auto
 func (stream SliceStream[E]) Parallel(goroutines int) SliceStream[E] {
 	stream.goroutines = goroutines
 	return stream
@@ -40,7 +41,8 @@ func (stream SliceStream[E]) At(index int) (elem E, ok bool) {
 // Support Parallel.
 func (stream SliceStream[E]) AllMatch(predicate func(E) bool) bool {
 	terminal := func(index int, v E) (isReturn bool, isComplete bool, ret bool) {
-		return !predicate(v), true, false
+		ok := predicate(v)
+		return !ok, !ok, false
 	}
 	result := stream.evaluationBool(terminal)
 	if result != nil {
@@ -55,7 +57,8 @@ func (stream SliceStream[E]) AllMatch(predicate func(E) bool) bool {
 // Support Parallel.
 func (stream SliceStream[E]) AnyMatch(predicate func(E) bool) bool {
 	terminal := func(index int, v E) (isReturn bool, isComplete bool, ret bool) {
-		return predicate(v), true, true
+		ok := predicate(v)
+		return ok, ok, true
 	}
 	result := stream.evaluationBool(terminal)
 	if result != nil {
@@ -66,7 +69,7 @@ func (stream SliceStream[E]) AnyMatch(predicate func(E) bool) bool {
 
 // Append appends elements to the end of this stream
 func (stream SliceStream[E]) Append(elements ...E) SliceStream[E] {
-	stream.evaluation()
+	stream.evaluationBy(StageInfo{Name: "Append"})
 	newSlice := make([]E, 0, len(stream.source)+len(elements))
 	newSlice = append(newSlice, stream.source...)
 	newSlice = append(newSlice, elements...)
@@ -96,7 +99,7 @@ func (stream SliceStream[E]) ForEach(action func(int, E)) SliceStream[E] {
 		action(index, v)
 		return true, false, v
 	}
-	stream.AddStage(stage)
+	stream.addStage(StageInfo{Name: "ForEach", Evaluation: true}, stage)
 	stream.evaluation()
 	return stream
 }
@@ -118,7 +121,8 @@ func (stream SliceStream[E]) First() (elem E, ok bool) {
 // Parallel side effect is that the element found may not be the first to appear
 func (stream SliceStream[E]) FindFunc(predicate func(E) bool) int {
 	terminal := func(index int, v E) (isReturn bool, isComplete bool, ret int) {
-		return predicate(v), true, index
+		ok := predicate(v)
+		return ok, ok, index
 	}
 	result := stream.evaluationInt(terminal)
 	if result != nil {
@@ -134,14 +138,14 @@ func (stream SliceStream[E]) Filter(predicate func(E) bool) SliceStream[E] {
 	stage := func(index int, e E) (isReturn bool, isComplete bool, ret E) {
 		return predicate(e), false, e
 	}
-	stream.AddStage(stage)
+	stream.addStage(StageInfo{Name: "Filter"}, stage)
 	return stream
 }
 
 // Insert inserts the values source... into s at index
 // If index is out of range then use Append to the end
 func (stream SliceStream[E]) Insert(index int, elements ...E) SliceStream[E] {
-	stream.evaluation()
+	stream.evaluationBy(StageInfo{Name: "Insert"})
 	if len(stream.source) <= index {
 		return stream.Append(elements...)
 	}
@@ -154,7 +158,7 @@ func (stream SliceStream[E]) Insert(index int, elements ...E) SliceStream[E] {
 // If i > j then swap i, j = j, i
 // If the source is empty or nil then do nothing
 func (stream SliceStream[E]) Delete(i, j int) SliceStream[E] {
-	stream.evaluation()
+	stream.evaluationBy(StageInfo{Name: "Delete"})
 	if i > j {
 		i, j = j, i
 	}
@@ -174,17 +178,30 @@ func (stream SliceStream[E]) IsSortedFunc(less func(a, b E) bool) bool {
 	return slices.IsSortedFunc(stream.source, less)
 }
 
+// Label Labels the last operation added to the stream, the label is shown by Explain.
+// If no operation has been added then do nothing.
+func (stream SliceStream[E]) Label(label string) SliceStream[E] {
+	stream.label(label)
+	return stream
+}
+
 // Limit Returns a stream consisting of the elements of this stream, truncated to be no longer than maxSize in length.
+// Short-circuiting, the elements after the first maxSize results are not processed, so it can limit an infinite source.
+//
+// Support Parallel.
 func (stream SliceStream[E]) Limit(maxSize int) SliceStream[E] {
-	stream.evaluation()
-	if stream.source == nil {
-		return stream
-	}
-	newSlice := make([]E, 0, maxSize)
-	for i := 0; i < len(stream.source) && i < maxSize; i++ {
-		newSlice = append(newSlice, stream.source[i])
-	}
-	stream.source = newSlice
+	n := 0
+	stream.addPushStage(StageInfo{Name: "Limit", ShortCircuit: true}, PushStage[E]{
+		Begin: func() { n = 0 },
+		Push: func(index int, e E, emit func(E) bool) bool {
+			n++
+			if n <= maxSize && !emit(e) {
+				return false
+			}
+			return n < maxSize
+		},
+		Stateful: true,
+	})
 	return stream
 }
 
@@ -197,7 +214,7 @@ func (stream SliceStream[E]) Map(mapper MapperFunc[E]) SliceStream[E] {
 	stage := func(index int, v E) (isReturn bool, isComplete bool, ret E) {
 		return true, false, mapper(v)
 	}
-	stream.AddStage(stage)
+	stream.addStage(StageInfo{Name: "Map"}, stage)
 	return stream
 }
 
@@ -233,20 +250,71 @@ func (stream SliceOrderedStream[E]) MinFunc(less func(a, b E) bool) (min E, ok b
 	return min, true
 }
 
+// Peek Returns a stream consisting of the elements of this stream,
+// additionally performing the action on each element as elements are consumed from the resulting stream.
+// Unlike ForEach, Peek is lazy and does not evaluate the stream.
+//
+// Support Parallel.
+// Parallel side effects are not executed in the original order of stream elements.
+func (stream SliceStream[E]) Peek(action func(int, E)) SliceStream[E] {
+	stage := func(index int, v E) (isReturn bool, isComplete bool, ret E) {
+		action(index, v)
+		return true, false, v
+	}
+	stream.addStage(StageInfo{Name: "Peek"}, stage)
+	return stream
+}
+
 // Reduce Returns a source consisting of the elements of this stream.
+// The elements are accumulated as they are evaluated, in their order.
 func (stream SliceStream[E]) Reduce(result E, accumulator func(result E, elem E) E) E {
-	stream.evaluation()
-	for _, v := range stream.source {
-		result = accumulator(result, v)
-	}
+	stream.each(func(e E) bool {
+		result = accumulator(result, e)
+		return true
+	})
 	return result
 }
 
 // SortFunc Returns a sorted stream consisting of the elements of this stream.
 // Sorted according to slices.SortFunc.
 func (stream SliceStream[E]) SortFunc(less func(a, b E) bool) SliceStream[E] {
-	stream.evaluation()
-	slices.SortFunc(stream.source, less)
+	stream.addPushStage(StageInfo{Name: "SortFunc"}, sorting(func(s []E) { slices.SortFunc(s, less) }))
+	return stream
+}
+
+// sorting Returns a stateful push stage holding the elements until the end of the input, then emitting them sorted by sort.
+func sorting[E any](sort func([]E)) PushStage[E] {
+	var held []E
+	return PushStage[E]{
+		Begin: func() { held = make([]E, 0) },
+		Push: func(index int, e E, emit func(E) bool) bool {
+			held = append(held, e)
+			return true
+		},
+		Flush: func(emit func(E) bool) bool {
+			sort(held)
+			for _, e := range held {
+				if !emit(e) {
+					return false
+				}
+			}
+			return true
+		},
+		Stateful: true,
+	}
+}
+
+// TakeWhile Returns a stream consisting of the longest prefix of elements of this stream that match the given predicate.
+// Short-circuiting, the elements after the first not matching element are not processed, so it can limit an infinite source.
+//
+// Support Parallel.
+func (stream SliceStream[E]) TakeWhile(predicate func(E) bool) SliceStream[E] {
+	stream.addPushStage(StageInfo{Name: "TakeWhile", ShortCircuit: true}, PushStage[E]{
+		Push: func(index int, e E, emit func(E) bool) bool {
+			return predicate(e) && emit(e)
+		},
+		Stateful: true,
+	})
 	return stream
 }
 
@@ -255,3 +323,10 @@ func (stream SliceStream[E]) ToSlice() []E {
 	stream.evaluation()
 	return stream.source
 }
+
+// ToSliceErr Returns a source in the stream and the error that stopped the source of the stream, if any.
+// See: Pipeline.Err
+func (stream SliceStream[E]) ToSliceErr() ([]E, error) {
+	stream.evaluation()
+	return stream.source, stream.err
+}
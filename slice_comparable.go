@@ -1,6 +1,11 @@
 package stream
 
-import "golang.org/x/exp/slices"
+import (
+	"io"
+	"math/rand"
+
+	"golang.org/x/exp/slices"
+)
 
 // SliceComparableStream Generics constraints based on comparable
 type SliceComparableStream[E comparable] struct {
@@ -14,22 +19,13 @@ func NewSliceByComparable[E comparable](source []E) SliceComparableStream[E] {
 
 // Distinct Returns a stream consisting of the distinct elements of this stream.
 // Remove duplicate according to map comparable.
+// The first occurrence of each element is kept, in the original order.
+//
+// Sequentially, the elements are emitted as they are evaluated, so Distinct works on unbounded sources.
+//
+// Support Parallel, the elements are hashed into shards deduplicated concurrently.
 func (stream SliceComparableStream[E]) Distinct() SliceComparableStream[E] {
-	stream.evaluation()
-	if stream.source == nil && len(stream.source) < 2 {
-		return stream
-	}
-
-	newSlice := make([]E, 0)
-	distinct := map[E]struct{}{}
-	for _, v := range stream.source {
-		if _, ok := distinct[v]; ok {
-			continue
-		}
-		distinct[v] = struct{}{}
-		newSlice = append(newSlice, v)
-	}
-	stream.source = newSlice
+	stream.addPushStage(StageInfo{Name: "Distinct"}, distinctStage(stream.Pipeline, func(e E) E { return e }))
 	return stream
 }
 
@@ -64,12 +60,48 @@ func (stream SliceComparableStream[E]) ForEach(action func(int, E)) SliceCompara
 	return stream
 }
 
+// BottomK See: SliceStream.BottomK
+func (stream SliceComparableStream[E]) BottomK(k int, less func(a, b E) bool) SliceComparableStream[E] {
+	stream.SliceStream = stream.SliceStream.BottomK(k, less)
+	return stream
+}
+
+// Checkpoint See: SliceStream.Checkpoint
+func (stream SliceComparableStream[E]) Checkpoint(store CheckpointStore, opts ...CheckpointOption) SliceComparableStream[E] {
+	stream.SliceStream = stream.SliceStream.Checkpoint(store, opts...)
+	return stream
+}
+
+// ExternalSort See: SliceStream.ExternalSort
+func (stream SliceComparableStream[E]) ExternalSort(less func(a, b E) bool, codec Codec[E], memoryLimit int, tmpDir string) SliceComparableStream[E] {
+	stream.SliceStream = stream.SliceStream.ExternalSort(less, codec, memoryLimit, tmpDir)
+	return stream
+}
+
 // Filter See: SliceStream.Filter
 func (stream SliceComparableStream[E]) Filter(predicate func(E) bool) SliceComparableStream[E] {
 	stream.SliceStream = stream.SliceStream.Filter(predicate)
 	return stream
 }
 
+// FlatMap See: SliceStream.FlatMap
+func (stream SliceComparableStream[E]) FlatMap(mapper func(E) []E) SliceComparableStream[E] {
+	stream.SliceStream = stream.SliceStream.FlatMap(mapper)
+	return stream
+}
+
+// Label See: SliceStream.Label
+func (stream SliceComparableStream[E]) Label(label string) SliceComparableStream[E] {
+	stream.SliceStream = stream.SliceStream.Label(label)
+	return stream
+}
+
+// Observe See: SliceStream.Observe
+func (stream SliceComparableStream[E]) Observe(observer Observer) SliceComparableStream[E] {
+	stream.SliceStream = stream.SliceStream.Observe(observer)
+	return stream
+}
+
 // Limit See: SliceStream.Limit
 func (stream SliceComparableStream[E]) Limit(maxSize int) SliceComparableStream[E] {
 	stream.SliceStream = stream.SliceStream.Limit(maxSize)
@@ -82,8 +114,80 @@ func (stream SliceComparableStream[E]) Map(mapper func(E) E) SliceComparableStre
 	return stream
 }
 
+// Peek See: SliceStream.Peek
+func (stream SliceComparableStream[E]) Peek(action func(int, E)) SliceComparableStream[E] {
+	stream.SliceStream = stream.SliceStream.Peek(action)
+	return stream
+}
+
+// Sample See: SliceStream.Sample
+func (stream SliceComparableStream[E]) Sample(k int, src rand.Source) SliceComparableStream[E] {
+	stream.SliceStream = stream.SliceStream.Sample(k, src)
+	return stream
+}
+
+// SampleFraction See: SliceStream.SampleFraction
+func (stream SliceComparableStream[E]) SampleFraction(p float64, src rand.Source) SliceComparableStream[E] {
+	stream.SliceStream = stream.SliceStream.SampleFraction(p, src)
+	return stream
+}
+
+// Shuffle See: SliceStream.Shuffle
+func (stream SliceComparableStream[E]) Shuffle(src rand.Source) SliceComparableStream[E] {
+	stream.SliceStream = stream.SliceStream.Shuffle(src)
+	return stream
+}
+
 // SortFunc See: SliceStream.SortFunc
 func (stream SliceComparableStream[E]) SortFunc(less func(a, b E) bool) SliceComparableStream[E] {
 	stream.SliceStream = stream.SliceStream.SortFunc(less)
 	return stream
 }
+
+// TakeWhile See: SliceStream.TakeWhile
+func (stream SliceComparableStream[E]) TakeWhile(predicate func(E) bool) SliceComparableStream[E] {
+	stream.SliceStream = stream.SliceStream.TakeWhile(predicate)
+	return stream
+}
+
+// Tap See: SliceStream.Tap
+func (stream SliceComparableStream[E]) Tap(w io.Writer, rate float64) SliceComparableStream[E] {
+	stream.SliceStream = stream.SliceStream.Tap(w, rate)
+	return stream
+}
+
+// TapLogger See: SliceStream.TapLogger
+func (stream SliceComparableStream[E]) TapLogger(logger Logger, rate float64) SliceComparableStream[E] {
+	stream.SliceStream = stream.SliceStream.TapLogger(logger, rate)
+	return stream
+}
+
+// TopK See: SliceStream.TopK
+func (stream SliceComparableStream[E]) TopK(k int, less func(a, b E) bool) SliceComparableStream[E] {
+	stream.SliceStream = stream.SliceStream.TopK(k, less)
+	return stream
+}
+
+// Trace See: SliceStream.Trace
+func (stream SliceComparableStream[E]) Trace(tracer Tracer) SliceComparableStream[E] {
+	stream.SliceStream = stream.SliceStream.Trace(tracer)
+	return stream
+}
+
+// WithExecutor See: SliceStream.WithExecutor
+func (stream SliceComparableStream[E]) WithExecutor(executor Executor) SliceComparableStream[E] {
+	stream.SliceStream = stream.SliceStream.WithExecutor(executor)
+	return stream
+}
+
+// WithProfile See: SliceStream.WithProfile
+func (stream SliceComparableStream[E]) WithProfile(profile AutoProfile) SliceComparableStream[E] {
+	stream.SliceStream = stream.SliceStream.WithProfile(profile)
+	return stream
+}
+
+// WithPartitioner See: SliceStream.WithPartitioner
+func (stream SliceComparableStream[E]) WithPartitioner(partitioner Partitioner[E]) SliceComparableStream[E] {
+	stream.SliceStream = stream.SliceStream.WithPartitioner(partitioner)
+	return stream
+}
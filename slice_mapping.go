@@ -1,5 +1,10 @@
 package stream
 
+import (
+	"io"
+	"math/rand"
+)
+
 // SliceMappingStream  Need to convert the type of source elements.
 // - E elements type
 // - MapE map elements type
@@ -21,27 +26,20 @@ func NewSliceByMapping[E any, MapE any, ReduceE any](source []E) SliceMappingStr
 //
 // Support Parallel.
 func (stream SliceMappingStream[E, MapE, ReduceE]) Map(mapper func(E) MapE) SliceMappingStream[MapE, MapE, ReduceE] {
-	if stream.source == nil {
+	if stream.source == nil && stream.gen == nil {
 		return NewSliceByMapping[MapE, MapE, ReduceE](nil)
 	}
-
-	terminal := func(index int, v E) (isReturn bool, isComplete bool, ret MapE) {
-		return true, false, mapper(v)
-	}
-	ret := pipelineRun(stream.Pipeline, wrapTerminal(stream.stages, terminal))
-	return NewSliceByMapping[MapE, MapE, ReduceE](ret)
+	pipe := pipelineMap(stream.Pipeline, StageInfo{Name: "Map"}, mapper)
+	return SliceMappingStream[MapE, MapE, ReduceE]{SliceStream: SliceStream[MapE]{Pipeline: pipe}}
 }
 
 // Reduce Returns a source consisting of the elements of this stream.
+// The elements are accumulated as they are evaluated, in their order.
 func (stream SliceMappingStream[E, MapE, ReduceE]) Reduce(result ReduceE, accumulator func(result ReduceE, elem E) ReduceE) ReduceE {
-	stream.evaluation()
-	if len(stream.source) == 0 {
-		return result
-	}
-
-	for _, v := range stream.source {
-		result = accumulator(result, v)
-	}
+	stream.each(func(e E) bool {
+		result = accumulator(result, e)
+		return true
+	})
 	return result
 }
 
@@ -57,20 +55,128 @@ func (stream SliceMappingStream[E, MapE, ReduceE]) ForEach(action func(int, E))
 	return stream
 }
 
+// BottomK See: SliceStream.BottomK
+func (stream SliceMappingStream[E, MapE, ReduceE]) BottomK(k int, less func(a, b E) bool) SliceMappingStream[E, MapE, ReduceE] {
+	stream.SliceStream = stream.SliceStream.BottomK(k, less)
+	return stream
+}
+
+// Checkpoint See: SliceStream.Checkpoint
+func (stream SliceMappingStream[E, MapE, ReduceE]) Checkpoint(store CheckpointStore, opts ...CheckpointOption) SliceMappingStream[E, MapE, ReduceE] {
+	stream.SliceStream = stream.SliceStream.Checkpoint(store, opts...)
+	return stream
+}
+
+// ExternalSort See: SliceStream.ExternalSort
+func (stream SliceMappingStream[E, MapE, ReduceE]) ExternalSort(less func(a, b E) bool, codec Codec[E], memoryLimit int, tmpDir string) SliceMappingStream[E, MapE, ReduceE] {
+	stream.SliceStream = stream.SliceStream.ExternalSort(less, codec, memoryLimit, tmpDir)
+	return stream
+}
+
 // Filter See: SliceStream.Filter
 func (stream SliceMappingStream[E, MapE, ReduceE]) Filter(predicate func(E) bool) SliceMappingStream[E, MapE, ReduceE] {
 	stream.SliceStream = stream.SliceStream.Filter(predicate)
 	return stream
 }
 
+// FlatMap See: SliceStream.FlatMap
+func (stream SliceMappingStream[E, MapE, ReduceE]) FlatMap(mapper func(E) []E) SliceMappingStream[E, MapE, ReduceE] {
+	stream.SliceStream = stream.SliceStream.FlatMap(mapper)
+	return stream
+}
+
+// Label See: SliceStream.Label
+func (stream SliceMappingStream[E, MapE, ReduceE]) Label(label string) SliceMappingStream[E, MapE, ReduceE] {
+	stream.SliceStream = stream.SliceStream.Label(label)
+	return stream
+}
+
+// Observe See: SliceStream.Observe
+func (stream SliceMappingStream[E, MapE, ReduceE]) Observe(observer Observer) SliceMappingStream[E, MapE, ReduceE] {
+	stream.SliceStream = stream.SliceStream.Observe(observer)
+	return stream
+}
+
 // Limit See: SliceStream.Limit
 func (stream SliceMappingStream[E, MapE, ReduceE]) Limit(maxSize int) SliceMappingStream[E, MapE, ReduceE] {
 	stream.SliceStream = stream.SliceStream.Limit(maxSize)
 	return stream
 }
 
+// Peek See: SliceStream.Peek
+func (stream SliceMappingStream[E, MapE, ReduceE]) Peek(action func(int, E)) SliceMappingStream[E, MapE, ReduceE] {
+	stream.SliceStream = stream.SliceStream.Peek(action)
+	return stream
+}
+
+// Sample See: SliceStream.Sample
+func (stream SliceMappingStream[E, MapE, ReduceE]) Sample(k int, src rand.Source) SliceMappingStream[E, MapE, ReduceE] {
+	stream.SliceStream = stream.SliceStream.Sample(k, src)
+	return stream
+}
+
+// SampleFraction See: SliceStream.SampleFraction
+func (stream SliceMappingStream[E, MapE, ReduceE]) SampleFraction(p float64, src rand.Source) SliceMappingStream[E, MapE, ReduceE] {
+	stream.SliceStream = stream.SliceStream.SampleFraction(p, src)
+	return stream
+}
+
+// Shuffle See: SliceStream.Shuffle
+func (stream SliceMappingStream[E, MapE, ReduceE]) Shuffle(src rand.Source) SliceMappingStream[E, MapE, ReduceE] {
+	stream.SliceStream = stream.SliceStream.Shuffle(src)
+	return stream
+}
+
 // SortFunc See: SliceStream.SortFunc
 func (stream SliceMappingStream[E, MapE, ReduceE]) SortFunc(less func(a, b E) bool) SliceMappingStream[E, MapE, ReduceE] {
 	stream.SliceStream = stream.SliceStream.SortFunc(less)
 	return stream
 }
+
+// TakeWhile See: SliceStream.TakeWhile
+func (stream SliceMappingStream[E, MapE, ReduceE]) TakeWhile(predicate func(E) bool) SliceMappingStream[E, MapE, ReduceE] {
+	stream.SliceStream = stream.SliceStream.TakeWhile(predicate)
+	return stream
+}
+
+// Tap See: SliceStream.Tap
+func (stream SliceMappingStream[E, MapE, ReduceE]) Tap(w io.Writer, rate float64) SliceMappingStream[E, MapE, ReduceE] {
+	stream.SliceStream = stream.SliceStream.Tap(w, rate)
+	return stream
+}
+
+// TapLogger See: SliceStream.TapLogger
+func (stream SliceMappingStream[E, MapE, ReduceE]) TapLogger(logger Logger, rate float64) SliceMappingStream[E, MapE, ReduceE] {
+	stream.SliceStream = stream.SliceStream.TapLogger(logger, rate)
+	return stream
+}
+
+// TopK See: SliceStream.TopK
+func (stream SliceMappingStream[E, MapE, ReduceE]) TopK(k int, less func(a, b E) bool) SliceMappingStream[E, MapE, ReduceE] {
+	stream.SliceStream = stream.SliceStream.TopK(k, less)
+	return stream
+}
+
+// Trace See: SliceStream.Trace
+func (stream SliceMappingStream[E, MapE, ReduceE]) Trace(tracer Tracer) SliceMappingStream[E, MapE, ReduceE] {
+	stream.SliceStream = stream.SliceStream.Trace(tracer)
+	return stream
+}
+
+// WithExecutor See: SliceStream.WithExecutor
+func (stream SliceMappingStream[E, MapE, ReduceE]) WithExecutor(executor Executor) SliceMappingStream[E, MapE, ReduceE] {
+	stream.SliceStream = stream.SliceStream.WithExecutor(executor)
+	return stream
+}
+
+// WithPartitioner See: SliceStream.WithPartitioner
+func (stream SliceMappingStream[E, MapE, ReduceE]) WithPartitioner(partitioner Partitioner[E]) SliceMappingStream[E, MapE, ReduceE] {
+	stream.SliceStream = stream.SliceStream.WithPartitioner(partitioner)
+	return stream
+}
+
+// WithProfile See: SliceStream.WithProfile
+func (stream SliceMappingStream[E, MapE, ReduceE]) WithProfile(profile AutoProfile) SliceMappingStream[E, MapE, ReduceE] {
+	stream.SliceStream = stream.SliceStream.WithProfile(profile)
+	return stream
+}
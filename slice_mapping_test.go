@@ -94,3 +94,9 @@ func TestParallelSliceMapping(t *testing.T) {
 		})
 	}
 }
+
+func TestSliceMappingMapNil(t *testing.T) {
+	got := NewSliceByMapping[int, string, string](nil).Parallel(4).Map(func(v int) string { return "" })
+	assert.Nil(t, got.ToSlice())
+	assert.Equal(t, 0, got.goroutines)
+}
@@ -1,6 +1,9 @@
 package stream
 
 import (
+	"io"
+	"math/rand"
+
 	"golang.org/x/exp/constraints"
 	"golang.org/x/exp/slices"
 )
@@ -58,8 +61,7 @@ func (stream SliceOrderedStream[E]) Min() (min E, ok bool) {
 // Sort Returns a sorted stream consisting of the elements of this stream.
 // Sorted according to slices.Sort.
 func (stream SliceOrderedStream[E]) Sort() SliceOrderedStream[E] {
-	stream.evaluation()
-	slices.Sort(stream.source)
+	stream.addPushStage(StageInfo{Name: "Sort"}, sorting(slices.Sort[E]))
 	return stream
 }
 
@@ -81,12 +83,48 @@ func (stream SliceOrderedStream[E]) ForEach(action func(int, E)) SliceOrderedStr
 	return stream
 }
 
+// BottomK See: SliceStream.BottomK
+func (stream SliceOrderedStream[E]) BottomK(k int, less func(a, b E) bool) SliceOrderedStream[E] {
+	stream.SliceStream = stream.SliceStream.BottomK(k, less)
+	return stream
+}
+
+// Checkpoint See: SliceStream.Checkpoint
+func (stream SliceOrderedStream[E]) Checkpoint(store CheckpointStore, opts ...CheckpointOption) SliceOrderedStream[E] {
+	stream.SliceStream = stream.SliceStream.Checkpoint(store, opts...)
+	return stream
+}
+
+// ExternalSort See: SliceStream.ExternalSort
+func (stream SliceOrderedStream[E]) ExternalSort(less func(a, b E) bool, codec Codec[E], memoryLimit int, tmpDir string) SliceOrderedStream[E] {
+	stream.SliceStream = stream.SliceStream.ExternalSort(less, codec, memoryLimit, tmpDir)
+	return stream
+}
+
 // Filter See: SliceStream.Filter
 func (stream SliceOrderedStream[E]) Filter(predicate func(E) bool) SliceOrderedStream[E] {
 	stream.SliceStream = stream.SliceStream.Filter(predicate)
 	return stream
 }
 
+// FlatMap See: SliceStream.FlatMap
+func (stream SliceOrderedStream[E]) FlatMap(mapper func(E) []E) SliceOrderedStream[E] {
+	stream.SliceStream = stream.SliceStream.FlatMap(mapper)
+	return stream
+}
+
+// Label See: SliceStream.Label
+func (stream SliceOrderedStream[E]) Label(label string) SliceOrderedStream[E] {
+	stream.SliceStream = stream.SliceStream.Label(label)
+	return stream
+}
+
+// Observe See: SliceStream.Observe
+func (stream SliceOrderedStream[E]) Observe(observer Observer) SliceOrderedStream[E] {
+	stream.SliceStream = stream.SliceStream.Observe(observer)
+	return stream
+}
+
 // Limit See: SliceStream.Limit
 func (stream SliceOrderedStream[E]) Limit(maxSize int) SliceOrderedStream[E] {
 	stream.SliceStream = stream.SliceStream.Limit(maxSize)
@@ -99,8 +137,80 @@ func (stream SliceOrderedStream[E]) Map(mapper func(E) E) SliceOrderedStream[E]
 	return stream
 }
 
+// Peek See: SliceStream.Peek
+func (stream SliceOrderedStream[E]) Peek(action func(int, E)) SliceOrderedStream[E] {
+	stream.SliceStream = stream.SliceStream.Peek(action)
+	return stream
+}
+
+// Sample See: SliceStream.Sample
+func (stream SliceOrderedStream[E]) Sample(k int, src rand.Source) SliceOrderedStream[E] {
+	stream.SliceStream = stream.SliceStream.Sample(k, src)
+	return stream
+}
+
+// SampleFraction See: SliceStream.SampleFraction
+func (stream SliceOrderedStream[E]) SampleFraction(p float64, src rand.Source) SliceOrderedStream[E] {
+	stream.SliceStream = stream.SliceStream.SampleFraction(p, src)
+	return stream
+}
+
+// Shuffle See: SliceStream.Shuffle
+func (stream SliceOrderedStream[E]) Shuffle(src rand.Source) SliceOrderedStream[E] {
+	stream.SliceStream = stream.SliceStream.Shuffle(src)
+	return stream
+}
+
 // SortFunc See: SliceStream.SortFunc
 func (stream SliceOrderedStream[E]) SortFunc(less func(a, b E) bool) SliceOrderedStream[E] {
 	stream.SliceStream = stream.SliceStream.SortFunc(less)
 	return stream
 }
+
+// TakeWhile See: SliceStream.TakeWhile
+func (stream SliceOrderedStream[E]) TakeWhile(predicate func(E) bool) SliceOrderedStream[E] {
+	stream.SliceStream = stream.SliceStream.TakeWhile(predicate)
+	return stream
+}
+
+// Tap See: SliceStream.Tap
+func (stream SliceOrderedStream[E]) Tap(w io.Writer, rate float64) SliceOrderedStream[E] {
+	stream.SliceStream = stream.SliceStream.Tap(w, rate)
+	return stream
+}
+
+// TapLogger See: SliceStream.TapLogger
+func (stream SliceOrderedStream[E]) TapLogger(logger Logger, rate float64) SliceOrderedStream[E] {
+	stream.SliceStream = stream.SliceStream.TapLogger(logger, rate)
+	return stream
+}
+
+// TopK See: SliceStream.TopK
+func (stream SliceOrderedStream[E]) TopK(k int, less func(a, b E) bool) SliceOrderedStream[E] {
+	stream.SliceStream = stream.SliceStream.TopK(k, less)
+	return stream
+}
+
+// Trace See: SliceStream.Trace
+func (stream SliceOrderedStream[E]) Trace(tracer Tracer) SliceOrderedStream[E] {
+	stream.SliceStream = stream.SliceStream.Trace(tracer)
+	return stream
+}
+
+// WithExecutor See: SliceStream.WithExecutor
+func (stream SliceOrderedStream[E]) WithExecutor(executor Executor) SliceOrderedStream[E] {
+	stream.SliceStream = stream.SliceStream.WithExecutor(executor)
+	return stream
+}
+
+// WithPartitioner See: SliceStream.WithPartitioner
+func (stream SliceOrderedStream[E]) WithPartitioner(partitioner Partitioner[E]) SliceOrderedStream[E] {
+	stream.SliceStream = stream.SliceStream.WithPartitioner(partitioner)
+	return stream
+}
+
+// WithProfile See: SliceStream.WithProfile
+func (stream SliceOrderedStream[E]) WithProfile(profile AutoProfile) SliceOrderedStream[E] {
+	stream.SliceStream = stream.SliceStream.WithProfile(profile)
+	return stream
+}
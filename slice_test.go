@@ -984,6 +984,54 @@ func TestSliceMinFunc(t *testing.T) {
 	}
 }
 
+func TestSlicePeek(t *testing.T) {
+	tests := []struct {
+		name  string
+		input []int
+		want  []int
+	}{
+		{
+			name:  "case",
+			input: []int{1, 2, 3},
+			want:  []int{20, 30},
+		},
+		{
+			name:  "empty",
+			input: []int{},
+			want:  []int{},
+		},
+		{
+			name:  "nil",
+			input: nil,
+			want:  nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var peeked []int
+			s := NewSlice(tt.input).
+				Filter(func(v int) bool { return v > 1 }).
+				Peek(func(i int, v int) { peeked = append(peeked, v) }).
+				Map(func(v int) int { return v * 10 })
+			assert.Nil(t, peeked)
+			assert.Equal(t, tt.want, s.ToSlice())
+			for i, v := range peeked {
+				assert.Equal(t, tt.want[i], v*10)
+			}
+
+			var count int64
+			got := NewSlice(tt.input).
+				Parallel(2).
+				Filter(func(v int) bool { return v > 1 }).
+				Peek(func(i int, v int) { atomic.AddInt64(&count, 1) }).
+				Map(func(v int) int { return v * 10 }).
+				ToSlice()
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, int64(len(tt.want)), count)
+		})
+	}
+}
+
 func TestSliceReduce(t *testing.T) {
 	tests := []struct {
 		name        string
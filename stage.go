@@ -0,0 +1,133 @@
+package stream
+
+// PushStage Stage of the stream processing the elements pushed by the previous stage.
+// Unlike Stage, it can pass zero or more results for each element to emit,
+// and hold a state across the elements of an evaluation, released by Flush at the end of the input.
+//
+// A stateless stage is run by the goroutines of Parallel, like Stage.
+// A stateful stage is run by the evaluating goroutine over the elements in their order,
+// then the stateless stages after it are run by the goroutines of Parallel over batches of its results,
+// or by the evaluating goroutine too if the size of the source is unknown.
+// The stages after a stateful stage index their input elements by their position instead of their index in the source.
+type PushStage[E any] struct {
+	// Begin Called once before the first element of an evaluation, optional.
+	Begin func()
+	// Push Processes the element at index, passing its results to emit.
+	// Returns false if the evaluation completes after this element, or if emit returned false.
+	Push func(index int, e E, emit func(E) bool) (more bool)
+	// Flush Called once after the last element of an evaluation of a stateful stage, passing the results it holds to emit.
+	// Returns false if emit returned false. Optional.
+	Flush func(emit func(E) bool) (more bool)
+	// Stateful Whether the stage holds a state across the elements, so it must process them sequentially and in order.
+	Stateful bool
+}
+
+// AdaptStage Returns a stateless PushStage running the stage, so Stage functions can be mixed with push stages.
+func AdaptStage[E any](stage Stage[E, E]) PushStage[E] {
+	return PushStage[E]{Push: stageFlow(stage)}
+}
+
+// AddPushStage Adds the push stage to the pipeline.
+func (pipe *Pipeline[E]) AddPushStage(stage PushStage[E]) {
+	pipe.addPushStage(StageInfo{Name: "PushStage"}, stage)
+}
+
+func (pipe *Pipeline[E]) addPushStage(info StageInfo, stage PushStage[E]) {
+	info.Stateful = info.Stateful || stage.Stateful
+	info = pipe.record(info)
+	stage.Stateful = info.Stateful
+	stage = pipe.observePush(info, stage)
+	// The pending stages keep their order before the push stage.
+	if pipe.stages != nil {
+		pipe.nodes = append(pipe.nodes, PushStage[E]{Push: stageFlow(pipe.stages)})
+		pipe.stages = nil
+	}
+	pipe.nodes = append(pipe.nodes, stage)
+	if info.ShortCircuit {
+		pipe.shortCircuit = true
+	}
+}
+
+// flow Processes the element at index, passing its zero or more results to emit.
+// Returns false if the evaluation completes after this element, or if emit returned false.
+type flow[E any, R any] func(index int, e E, emit func(R) bool) (more bool)
+
+// stageFlow Returns the flow running the stage.
+func stageFlow[E any, R any](stage Stage[E, R]) flow[E, R] {
+	return func(index int, e E, emit func(R) bool) bool {
+		isReturn, isComplete, ret := stage(index, e)
+		if isReturn && !emit(ret) {
+			return false
+		}
+		return !isComplete
+	}
+}
+
+// chainFlow Returns the flow running the push stages then last, the elements keep their index in the source.
+func chainFlow[E any, R any](nodes []PushStage[E], last flow[E, R]) flow[E, R] {
+	f := last
+	for i := len(nodes) - 1; i >= 0; i-- {
+		push, next := nodes[i].Push, f
+		f = func(index int, e E, emit func(R) bool) bool {
+			return push(index, e, func(x E) bool { return next(index, x, emit) })
+		}
+	}
+	return f
+}
+
+// sequenceFlow Returns the push function running the push stages then last sequentially, passing the results to emit,
+// and the flush function flushing the stages in order at the end of the input.
+// Each stage indexes its input elements by their position, a stage that returned false receives no more elements.
+func sequenceFlow[E any, R any](nodes []PushStage[E], last flow[E, R], emit func(R) bool) (push func(E) bool, flush func()) {
+	n := len(nodes)
+	closed := make([]bool, n+1)
+	counts := make([]int, n+1)
+	steps := make([]func(E) bool, n+1)
+	steps[n] = func(e E) bool {
+		if closed[n] {
+			return false
+		}
+		index := counts[n]
+		counts[n]++
+		if !last(index, e, emit) {
+			closed[n] = true
+		}
+		return !closed[n]
+	}
+	for j := n - 1; j >= 0; j-- {
+		j, push, next := j, nodes[j].Push, steps[j+1]
+		steps[j] = func(e E) bool {
+			if closed[j] {
+				return false
+			}
+			index := counts[j]
+			counts[j]++
+			if !push(index, e, next) {
+				closed[j] = true
+			}
+			return !closed[j]
+		}
+	}
+
+	flush = func() {
+		for j, node := range nodes {
+			if closed[j] || node.Flush == nil {
+				continue
+			}
+			if !node.Flush(steps[j+1]) {
+				closed[j] = true
+			}
+		}
+	}
+	return steps[0], flush
+}
+
+// stateful Returns the index of the first stateful push stage, len(nodes) if there is none.
+func stateful[E any](nodes []PushStage[E]) int {
+	for i, node := range nodes {
+		if node.Stateful {
+			return i
+		}
+	}
+	return len(nodes)
+}
@@ -0,0 +1,213 @@
+package stream
+
+import (
+	"context"
+	"runtime"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// pairwise Returns a stateful push stage emitting the sum of each element with the previous one.
+func pairwise() PushStage[int] {
+	var prev int
+	var started bool
+	return PushStage[int]{
+		Begin: func() { started = false },
+		Push: func(index int, e int, emit func(int) bool) bool {
+			defer func() { prev, started = e, true }()
+			if !started {
+				return true
+			}
+			return emit(prev + e)
+		},
+		Flush: func(emit func(int) bool) bool {
+			return !started || emit(prev)
+		},
+		Stateful: true,
+	}
+}
+
+func TestPipelinePushStage(t *testing.T) {
+	tests := []struct {
+		name       string
+		input      []int
+		goroutines int
+		want       []int
+	}{
+		{
+			name:  "case",
+			input: []int{1, 2, 3, 4},
+			want:  []int{30, 50, 70, 40},
+		},
+		{
+			name:       "parallel",
+			input:      []int{1, 2, 3, 4},
+			goroutines: 3,
+			want:       []int{30, 50, 70, 40},
+		},
+		{
+			name:  "empty",
+			input: []int{},
+			want:  []int{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewSlice(tt.input).Parallel(tt.goroutines).Map(func(v int) int { return v * 10 })
+			s.AddPushStage(pairwise())
+			assert.Nil(t, s.stages)
+			got := s.Map(func(v int) int { return v }).ToSlice()
+			assert.Equal(t, tt.want, got)
+			assert.Nil(t, s.nodes)
+		})
+	}
+}
+
+func TestPipelinePushStageMultiOutput(t *testing.T) {
+	for _, goroutines := range []int{0, 4} {
+		s := Range(0, 1000, 1).Parallel(goroutines)
+		s.AddPushStage(PushStage[int]{
+			Push: func(index int, e int, emit func(int) bool) bool {
+				if e%2 == 0 {
+					return true
+				}
+				return emit(e) && emit(-e)
+			},
+		})
+		got := s.Limit(4).ToSlice()
+		assert.Equal(t, []int{1, -1, 3, -3}, got)
+	}
+}
+
+func TestPipelinePushStageEarlyStop(t *testing.T) {
+	expanding := func(stream SliceStream[int]) SliceStream[int] {
+		stream.AddPushStage(PushStage[int]{
+			Push: func(index int, e int, emit func(int) bool) bool {
+				for j := 0; j < 1000; j++ {
+					if !emit(e) {
+						return false
+					}
+				}
+				return true
+			},
+		})
+		return stream
+	}
+
+	// The partitions stop when the results are no longer consumed, rather than waiting for them forever.
+	before := runtime.NumGoroutine()
+	spawn := ExecutorFunc(func(task func()) { go task() })
+	for i := 0; i < 5; i++ {
+		got := expanding(NewSlice(make([]int, 1000)).WithExecutor(spawn).Parallel(4)).Limit(3).ToSlice()
+		assert.Equal(t, []int{0, 0, 0}, got)
+	}
+	for deadline := time.Now().Add(5 * time.Second); runtime.NumGoroutine() > before && time.Now().Before(deadline); {
+		time.Sleep(10 * time.Millisecond)
+	}
+	assert.LessOrEqual(t, runtime.NumGoroutine(), before)
+
+	// The workers of a pool are released.
+	pool := NewPool(4)
+	for i := 0; i < 5; i++ {
+		expanding(NewSlice(make([]int, 1000)).WithExecutor(pool).Parallel(4)).Limit(3).ToSlice()
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	assert.NoError(t, pool.Shutdown(ctx))
+}
+
+func TestPipelineParallelAfterStatefulStage(t *testing.T) {
+	source := Range(0, 64, 1).ToSlice()
+	sorted := NewSliceByOrdered(source).Parallel(4).Sort()
+	limited := NewSlice(source).Parallel(4).Limit(32)
+	for name, stream := range map[string]SliceStream[int]{"Sort": sorted.SliceStream, "Limit": limited} {
+		// Each element waits for another one to be processed at the same time.
+		var running, alone int32
+		got := stream.Map(func(v int) int {
+			atomic.AddInt32(&running, 1)
+			deadline := time.Now().Add(5 * time.Second)
+			for atomic.LoadInt32(&running) < 2 && time.Now().Before(deadline) {
+				runtime.Gosched()
+			}
+			if atomic.LoadInt32(&running) < 2 {
+				atomic.StoreInt32(&alone, 1)
+			}
+			return v * 2
+		}).ToSlice()
+		assert.Equal(t, int32(0), alone, name)
+		assert.Equal(t, Range(0, len(got)*2, 2).ToSlice(), got, name)
+	}
+}
+
+func TestAdaptStage(t *testing.T) {
+	s := NewSlice([]int{1, 2, 3})
+	s.AddPushStage(AdaptStage(func(index int, e int) (isReturn bool, isComplete bool, ret int) {
+		return e != 2, e == 3, e * index
+	}))
+	assert.Equal(t, []int{0, 6}, s.ToSlice())
+	assert.Equal(t, "Pipeline (sequential)\n  1. PushStage\n", s.Explain())
+}
+
+func TestPipelineFusedStages(t *testing.T) {
+	for _, goroutines := range []int{0, 4} {
+		got := NewSliceByOrdered([]int{5, 3, 5, 1, 3, 4, 2}).
+			Parallel(goroutines).
+			Distinct().
+			Sort().
+			Limit(3).
+			Map(func(v int) int { return v * 10 }).
+			ToSlice()
+		assert.Equal(t, []int{10, 20, 30}, got)
+
+		got = NewSliceByOrdered([]int{5, 3, 5, 1, 3, 4, 2}).
+			Parallel(goroutines).
+			Limit(4).
+			Sort().
+			ToSlice()
+		assert.Equal(t, []int{1, 3, 5, 5}, got)
+
+		got = RangeFrom(0, 1).
+			Parallel(goroutines).
+			Map(func(v int) int { return v % 7 }).
+			Distinct().
+			Limit(7).
+			Sort().
+			ToSlice()
+		assert.Equal(t, []int{0, 1, 2, 3, 4, 5, 6}, got)
+
+		index := NewSliceByOrdered([]int{30, 10, 20}).Parallel(goroutines).Sort().FindFunc(func(v int) bool { return v == 30 })
+		assert.Equal(t, 2, index)
+
+		sum := NewSliceByOrdered([]int{3, 1, 2, 3}).Parallel(goroutines).Distinct().Reduce(0, func(r int, e int) int { return r*10 + e })
+		assert.Equal(t, 312, sum)
+	}
+}
+
+func TestPipelineFusedStagesLazy(t *testing.T) {
+	processed := 0
+	s := NewSliceByOrdered([]int{3, 1, 2}).
+		Peek(func(int, int) { processed++ }).
+		Sort().
+		Distinct().
+		Limit(2)
+	assert.Equal(t, 0, processed)
+	assert.Equal(t, []int{1, 2}, s.ToSlice())
+	assert.Equal(t, 3, processed)
+}
+
+func TestPipelinePushStageObserve(t *testing.T) {
+	c := NewMetricsCollector()
+	NewSliceByOrdered([]int{3, 3, 1, 2}).Observe(c).Distinct().Limit(2).ToSlice()
+	stages := c.Stages()
+	assert.Equal(t, 2, len(stages))
+	assert.Equal(t, "Distinct", stages[0].Stage.Name)
+	assert.Equal(t, int64(3), stages[0].In)
+	assert.Equal(t, int64(2), stages[0].Out)
+	assert.Equal(t, int64(1), stages[0].Dropped)
+	assert.Equal(t, "Limit", stages[1].Stage.Name)
+	assert.Equal(t, int64(2), stages[1].In)
+	assert.Equal(t, int64(2), stages[1].Out)
+}
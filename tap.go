@@ -0,0 +1,63 @@
+package stream
+
+import (
+	"fmt"
+	"io"
+	"sync"
+	"sync/atomic"
+)
+
+// Logger Structured logger used by TapLogger, *slog.Logger satisfies it.
+type Logger interface {
+	Info(msg string, args ...any)
+}
+
+// sampler Deterministically samples the given rate of the elements, safe for concurrent use.
+type sampler struct {
+	rate float64
+	n    int64
+}
+
+func (s *sampler) sample() bool {
+	n := atomic.AddInt64(&s.n, 1)
+	return int64(float64(n)*s.rate) > int64(float64(n-1)*s.rate)
+}
+
+// Tap Returns a stream consisting of the elements of this stream,
+// additionally writing the index and the value of the sampled elements to w, one line per element.
+// - rate: the fraction of the elements written, 1 writes every element, 0.01 writes one element out of 100
+//
+// Support Parallel.
+// Parallel lines are not written in the original order of stream elements.
+func (stream SliceStream[E]) Tap(w io.Writer, rate float64) SliceStream[E] {
+	s := &sampler{rate: rate}
+	var mu sync.Mutex
+	stage := func(index int, v E) (isReturn bool, isComplete bool, ret E) {
+		if s.sample() {
+			mu.Lock()
+			fmt.Fprintf(w, "index=%d elem=%v\n", index, v)
+			mu.Unlock()
+		}
+		return true, false, v
+	}
+	stream.addStage(StageInfo{Name: "Tap"}, stage)
+	return stream
+}
+
+// TapLogger Returns a stream consisting of the elements of this stream,
+// additionally logging the index and the value of the sampled elements at info level.
+// - rate: the fraction of the elements logged, 1 logs every element, 0.01 logs one element out of 100
+//
+// Support Parallel.
+// Parallel records are not logged in the original order of stream elements.
+func (stream SliceStream[E]) TapLogger(logger Logger, rate float64) SliceStream[E] {
+	s := &sampler{rate: rate}
+	stage := func(index int, v E) (isReturn bool, isComplete bool, ret E) {
+		if s.sample() {
+			logger.Info("stream tap", "index", index, "elem", v)
+		}
+		return true, false, v
+	}
+	stream.addStage(StageInfo{Name: "TapLogger"}, stage)
+	return stream
+}
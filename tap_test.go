@@ -0,0 +1,90 @@
+package stream
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"sync"
+	"testing"
+)
+
+type testLogger struct {
+	mu      sync.Mutex
+	records []string
+}
+
+func (l *testLogger) Info(msg string, args ...any) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.records = append(l.records, strings.TrimSpace(fmt.Sprintln(append([]any{msg}, args...)...)))
+}
+
+func TestSliceTap(t *testing.T) {
+	tests := []struct {
+		name  string
+		input []int
+		rate  float64
+		want  string
+	}{
+		{
+			name:  "case",
+			input: []int{1, 2, 3},
+			rate:  1,
+			want:  "index=0 elem=1\nindex=1 elem=2\nindex=2 elem=3\n",
+		},
+		{
+			name:  "case",
+			input: []int{1, 2, 3, 4},
+			rate:  0.5,
+			want:  "index=1 elem=2\nindex=3 elem=4\n",
+		},
+		{
+			name:  "case",
+			input: []int{1, 2, 3, 4},
+			rate:  0,
+			want:  "",
+		},
+		{
+			name:  "nil",
+			input: nil,
+			rate:  1,
+			want:  "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			got := NewSlice(tt.input).Tap(&buf, tt.rate).ToSlice()
+			assert.Equal(t, tt.input, got)
+			assert.Equal(t, tt.want, buf.String())
+		})
+	}
+}
+
+func TestSliceTapParallel(t *testing.T) {
+	input := newArray(1000)
+	var buf bytes.Buffer
+	got := NewSlice(input).Parallel(8).Tap(&buf, 0.1).ToSlice()
+	assert.Equal(t, input, got)
+	assert.Equal(t, 100, strings.Count(buf.String(), "\n"))
+}
+
+func TestSliceTapLogger(t *testing.T) {
+	logger := &testLogger{}
+	got := NewSliceByOrdered([]int{3, 1, 2}).
+		TapLogger(logger, 1).
+		Filter(func(v int) bool { return v > 1 }).
+		Sort().
+		ToSlice()
+	assert.Equal(t, []int{2, 3}, got)
+	assert.Equal(t, []string{
+		"stream tap index 0 elem 3",
+		"stream tap index 1 elem 1",
+		"stream tap index 2 elem 2",
+	}, logger.records)
+
+	logger = &testLogger{}
+	NewSlice(newArray(100)).Parallel(4).TapLogger(logger, 0.25).ToSlice()
+	assert.Equal(t, 25, len(logger.records))
+}
@@ -0,0 +1,230 @@
+package stream
+
+import (
+	"container/heap"
+	"sync"
+
+	"golang.org/x/exp/constraints"
+	"golang.org/x/exp/slices"
+)
+
+// TopK Returns a stream consisting of the k greatest elements of this stream according to less, in descending order.
+// Equal elements are kept in their original order. If the stream has fewer than k elements then all of them are returned.
+// The elements are selected with a heap of k elements in O(n log k), without sorting the whole stream.
+//
+// Support Parallel, each partition selects the elements with its own heap, then the heaps are merged.
+func (stream SliceStream[E]) TopK(k int, less func(a, b E) bool) SliceStream[E] {
+	stream.selectK(StageInfo{Name: "TopK"}, k, func(a, b ranked[E]) bool {
+		if less(b.elem, a.elem) {
+			return true
+		}
+		return !less(a.elem, b.elem) && a.index < b.index
+	})
+	return stream
+}
+
+// BottomK Returns a stream consisting of the k least elements of this stream according to less, in ascending order.
+// Equal elements are kept in their original order. If the stream has fewer than k elements then all of them are returned.
+// The elements are selected with a heap of k elements in O(n log k), without sorting the whole stream.
+//
+// Support Parallel, each partition selects the elements with its own heap, then the heaps are merged.
+func (stream SliceStream[E]) BottomK(k int, less func(a, b E) bool) SliceStream[E] {
+	stream.selectK(StageInfo{Name: "BottomK"}, k, func(a, b ranked[E]) bool {
+		if less(a.elem, b.elem) {
+			return true
+		}
+		return !less(b.elem, a.elem) && a.index < b.index
+	})
+	return stream
+}
+
+// TopKBy Returns a stream consisting of the k elements of the stream with the greatest keys, in descending order of the keys.
+// See: SliceStream.TopK
+//
+// Support Parallel.
+func TopKBy[E any, K constraints.Ordered](stream SliceStream[E], k int, key func(E) K) SliceStream[E] {
+	stream.selectK(StageInfo{Name: "TopKBy"}, k, func(a, b ranked[E]) bool {
+		ka, kb := key(a.elem), key(b.elem)
+		if ka != kb {
+			return ka > kb
+		}
+		return a.index < b.index
+	})
+	return stream
+}
+
+// selectK Evaluates the pending stages, keeping the k first elements ordered by before as the source of the pipeline.
+func (pipe *Pipeline[E]) selectK(info StageInfo, k int, before func(a, b ranked[E]) bool) {
+	info.Evaluation = true
+	info.Stateful = true
+	pipe.record(info)
+	if pipe.source == nil && pipe.gen == nil {
+		return
+	}
+	pipe.bounded()
+
+	// Each partition of Parallel selects the elements with its own heap, taken once, then the heaps are merged.
+	var mu sync.Mutex
+	var heaps, free []*boundedHeap[E]
+	acquire := func() *boundedHeap[E] {
+		mu.Lock()
+		defer mu.Unlock()
+		if n := len(free); n > 0 {
+			h := free[n-1]
+			free = free[:n-1]
+			return h
+		}
+		h := &boundedHeap[E]{k: k, before: before}
+		heaps = append(heaps, h)
+		return h
+	}
+	offer := func(h *boundedHeap[E]) func(ranked[E]) bool {
+		return func(r ranked[E]) bool {
+			h.offer(r)
+			return true
+		}
+	}
+	local := func() (func(ranked[E]) bool, func()) {
+		h := acquire()
+		return offer(h), func() {
+			mu.Lock()
+			free = append(free, h)
+			mu.Unlock()
+		}
+	}
+	terminal := func(index int, e E) (isReturn bool, isComplete bool, ret ranked[E]) {
+		return true, false, ranked[E]{index: index, elem: e}
+	}
+	pipelineEachLocal(pipe, wrapTerminal(pipe.stages, terminal), offer(acquire()), local)
+
+	merged := &boundedHeap[E]{k: k, before: before}
+	for _, h := range heaps {
+		for _, r := range h.items {
+			merged.offer(r)
+		}
+	}
+	slices.SortFunc(merged.items, before)
+	pipe.source = make([]E, 0, len(merged.items))
+	for _, r := range merged.items {
+		pipe.source = append(pipe.source, r.elem)
+	}
+}
+
+// ranked Element of the stream with its index, the index breaks the ties of the selection.
+type ranked[E any] struct {
+	index int
+	elem  E
+}
+
+// boundedHeap Keeps the k first elements ordered by before, the root is the last of them.
+// Implements heap.Interface.
+type boundedHeap[E any] struct {
+	k      int
+	items  []ranked[E]
+	before func(a, b ranked[E]) bool
+}
+
+// offer Keeps the element if it is before the last kept element, or if fewer than k elements are kept.
+func (h *boundedHeap[E]) offer(r ranked[E]) {
+	if h.k <= 0 {
+		return
+	}
+	if len(h.items) < h.k {
+		heap.Push(h, r)
+		return
+	}
+	if h.before(r, h.items[0]) {
+		h.items[0] = r
+		heap.Fix(h, 0)
+	}
+}
+
+func (h *boundedHeap[E]) Len() int {
+	return len(h.items)
+}
+
+func (h *boundedHeap[E]) Less(i, j int) bool {
+	return h.before(h.items[j], h.items[i])
+}
+
+func (h *boundedHeap[E]) Swap(i, j int) {
+	h.items[i], h.items[j] = h.items[j], h.items[i]
+}
+
+func (h *boundedHeap[E]) Push(x any) {
+	h.items = append(h.items, x.(ranked[E]))
+}
+
+func (h *boundedHeap[E]) Pop() any {
+	r := h.items[len(h.items)-1]
+	h.items = h.items[:len(h.items)-1]
+	return r
+}
+
+// NthElement Returns the n-th least element of this stream (from 0), as if the stream was sorted in ascending order.
+// The element is selected with quickselect in O(n) on average, without sorting the whole stream.
+// If n is out of range then E Type default value is returned. ok return false
+func (stream SliceOrderedStream[E]) NthElement(n int) (elem E, ok bool) {
+	stream.evaluation()
+	if n < 0 || n >= len(stream.source) {
+		return
+	}
+	return quickselect(slices.Clone(stream.source), n), true
+}
+
+// Median Returns the median element of this stream, the lower one if the stream has an even number of elements.
+// See: SliceOrderedStream.NthElement
+// If the source is empty or nil then E Type default value is returned. ok return false
+func (stream SliceOrderedStream[E]) Median() (median E, ok bool) {
+	stream.evaluation()
+	if len(stream.source) == 0 {
+		return
+	}
+	return quickselect(slices.Clone(stream.source), (len(stream.source)-1)/2), true
+}
+
+// quickselect Returns the n-th least element of s, reordering s.
+// The elements equal to the pivot are grouped by a three-way partition, so many equal elements do not make it quadratic.
+func quickselect[E constraints.Ordered](s []E, n int) E {
+	low, high := 0, len(s)-1
+	for low < high {
+		// Median of three pivot.
+		mid := low + (high-low)/2
+		if s[mid] < s[low] {
+			s[mid], s[low] = s[low], s[mid]
+		}
+		if s[high] < s[low] {
+			s[high], s[low] = s[low], s[high]
+		}
+		if s[high] < s[mid] {
+			s[high], s[mid] = s[mid], s[high]
+		}
+		pivot := s[mid]
+
+		// s[low:lt] < pivot, s[lt:i] == pivot, s[gt+1:high+1] > pivot.
+		lt, i, gt := low, low, high
+		for i <= gt {
+			switch {
+			case s[i] < pivot:
+				s[lt], s[i] = s[i], s[lt]
+				lt++
+				i++
+			case pivot < s[i]:
+				s[i], s[gt] = s[gt], s[i]
+				gt--
+			default:
+				i++
+			}
+		}
+
+		switch {
+		case n < lt:
+			high = lt - 1
+		case n > gt:
+			low = gt + 1
+		default:
+			return s[n]
+		}
+	}
+	return s[n]
+}
@@ -0,0 +1,188 @@
+package stream
+
+import (
+	"github.com/stretchr/testify/assert"
+	"math/rand"
+	"testing"
+
+	"golang.org/x/exp/slices"
+)
+
+func TestSliceTopK(t *testing.T) {
+	tests := []struct {
+		name       string
+		input      []int
+		k          int
+		goroutines int
+		want       []int
+	}{
+		{
+			name:  "case",
+			input: []int{5, 1, 9, 3, 7, 2},
+			k:     3,
+			want:  []int{9, 7, 5},
+		},
+		{
+			name:       "parallel",
+			input:      Range(0, 1000, 1).ToSlice(),
+			k:          4,
+			goroutines: 4,
+			want:       []int{999, 998, 997, 996},
+		},
+		{
+			name:  "fewer than k",
+			input: []int{2, 1},
+			k:     3,
+			want:  []int{2, 1},
+		},
+		{
+			name:  "zero",
+			input: []int{2, 1},
+			k:     0,
+			want:  []int{},
+		},
+		{
+			name:  "nil",
+			input: nil,
+			k:     3,
+			want:  nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := NewSlice(tt.input).Parallel(tt.goroutines).TopK(tt.k, func(a, b int) bool { return a < b }).ToSlice()
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestSliceBottomK(t *testing.T) {
+	input := make([]int, 10000)
+	r := rand.New(rand.NewSource(1))
+	for i := range input {
+		input[i] = r.Intn(1000)
+	}
+	want := slices.Clone(input)
+	slices.Sort(want)
+
+	for _, goroutines := range []int{0, 3, 8} {
+		got := NewSlice(input).
+			Parallel(goroutines).
+			Filter(func(v int) bool { return v%2 == 0 }).
+			BottomK(5, func(a, b int) bool { return a < b }).
+			ToSlice()
+		assert.Equal(t, NewSlice(want).Filter(func(v int) bool { return v%2 == 0 }).Limit(5).ToSlice(), got)
+	}
+}
+
+func TestSliceTopKBatches(t *testing.T) {
+	// The generator is evaluated by several batches of partitions, each partition reuses a heap of a previous one.
+	got := Range(0, 100000, 1).
+		Parallel(4).
+		Map(func(v int) int { return v % 1000 }).
+		TopK(3, func(a, b int) bool { return a < b }).
+		ToSlice()
+	assert.Equal(t, []int{999, 999, 999}, got)
+}
+
+func TestTopKBy(t *testing.T) {
+	type order struct {
+		ID    int
+		Price int
+	}
+	orders := []order{{1, 10}, {2, 30}, {3, 20}, {4, 30}, {5, 5}}
+	for _, goroutines := range []int{0, 2} {
+		got := TopKBy(NewSlice(orders).Parallel(goroutines), 3, func(o order) int { return o.Price }).ToSlice()
+		assert.Equal(t, []order{{2, 30}, {4, 30}, {3, 20}}, got)
+	}
+
+	got := TopKBy(FromChan(sendAll([]order{{1, 1}, {2, 2}})), 1, func(o order) int { return o.Price }).ToSlice()
+	assert.Equal(t, []order{{2, 2}}, got)
+}
+
+func TestSliceTopKInfinite(t *testing.T) {
+	assert.Panics(t, func() {
+		RangeFrom(0, 1).TopK(1, func(a, b int) bool { return a < b })
+	})
+}
+
+func TestSliceOrderedNthElement(t *testing.T) {
+	input := []int{9, 4, 7, 1, 8, 2, 2, 6}
+	sorted := slices.Clone(input)
+	slices.Sort(sorted)
+	for n := range input {
+		got, ok := NewSliceByOrdered(input).NthElement(n)
+		assert.True(t, ok)
+		assert.Equal(t, sorted[n], got)
+	}
+	assert.Equal(t, []int{9, 4, 7, 1, 8, 2, 2, 6}, input)
+
+	_, ok := NewSliceByOrdered(input).NthElement(len(input))
+	assert.False(t, ok)
+	_, ok = NewSliceByOrdered([]int(nil)).NthElement(0)
+	assert.False(t, ok)
+}
+
+func TestSliceOrderedNthElementDuplicates(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	input := make([]int, 1000)
+	for i := range input {
+		input[i] = r.Intn(5)
+	}
+	sorted := slices.Clone(input)
+	slices.Sort(sorted)
+	for _, n := range []int{0, 1, 250, 499, 500, 998, 999} {
+		got, _ := NewSliceByOrdered(input).NthElement(n)
+		assert.Equal(t, sorted[n], got)
+	}
+
+	// Equal elements are selected in linear time.
+	zeros := make([]int, 1000000)
+	got, ok := NewSliceByOrdered(zeros).Median()
+	assert.True(t, ok)
+	assert.Equal(t, 0, got)
+}
+
+func TestSliceOrderedMedian(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  []float64
+		want   float64
+		wantOk bool
+	}{
+		{
+			name:   "odd",
+			input:  []float64{3, 1, 2},
+			want:   2,
+			wantOk: true,
+		},
+		{
+			name:   "even",
+			input:  []float64{4, 1, 3, 2},
+			want:   2,
+			wantOk: true,
+		},
+		{
+			name:   "nil",
+			input:  nil,
+			want:   0,
+			wantOk: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := NewSliceByOrdered(tt.input).Parallel(2).Median()
+			assert.Equal(t, tt.wantOk, ok)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func sendAll[E any](elems []E) <-chan E {
+	ch := make(chan E, len(elems))
+	for _, e := range elems {
+		ch <- e
+	}
+	close(ch)
+	return ch
+}
@@ -0,0 +1,168 @@
+package stream
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Tracer Creates spans for the stream evaluations.
+// Compatible in shape with the OpenTelemetry trace.Tracer, so an adapter is a few lines of code.
+//
+// Every terminal evaluation creates a `stream.evaluation` span,
+// every stage and every partition of Parallel creates a child span of it.
+type Tracer interface {
+	// Start Creates a span and a context.Context containing the newly-created span.
+	Start(ctx context.Context, name string) (context.Context, Span)
+}
+
+// Span A single operation within a trace. Compatible in shape with the OpenTelemetry trace.Span.
+type Span interface {
+	// SetAttributes Sets attributes of the span.
+	SetAttributes(attrs ...Attribute)
+	// RecordError Records an error of the operation.
+	RecordError(err error)
+	// End Completes the span.
+	End()
+}
+
+// Attribute Key-value pair describing a span.
+type Attribute struct {
+	Key   string
+	Value any
+}
+
+// NoopTracer Tracer that creates spans doing nothing, used when no tracer is attached.
+type NoopTracer struct{}
+
+// Start See: Tracer.Start
+func (NoopTracer) Start(ctx context.Context, _ string) (context.Context, Span) {
+	return ctx, noopSpan{}
+}
+
+type noopSpan struct{}
+
+func (noopSpan) SetAttributes(...Attribute) {}
+func (noopSpan) RecordError(error)          {}
+func (noopSpan) End()                       {}
+
+// startSpan Starts a span with the tracer, NoopTracer is used if the tracer is nil.
+func startSpan(tracer Tracer, ctx context.Context, name string) (context.Context, Span) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if tracer == nil {
+		return NoopTracer{}.Start(ctx, name)
+	}
+	return tracer.Start(ctx, name)
+}
+
+// panicError Converts a recovered panic value to an error to record it on a span.
+func panicError(r any) error {
+	if err, ok := r.(error); ok {
+		return fmt.Errorf("panic: %w", err)
+	}
+	return fmt.Errorf("panic: %v", r)
+}
+
+// Trace Attaches the tracer to the stream, every following evaluation is traced.
+// Trace(nil) detaches the tracer.
+func (stream SliceStream[E]) Trace(tracer Tracer) SliceStream[E] {
+	stream.tracer = tracer
+	return stream
+}
+
+// RecordedSpan A span recorded by RecordingTracer.
+type RecordedSpan struct {
+	ID int
+	// ParentID The ID of the parent span, 0 if the span is a root span.
+	ParentID   int
+	Name       string
+	Attributes map[string]any
+	Errors     []error
+	Start      time.Time
+	End        time.Time
+	// Ended Whether Span.End has been called.
+	Ended bool
+}
+
+// RecordingTracer In-memory Tracer, records the spans for tests and debugging.
+type RecordingTracer struct {
+	mu    sync.Mutex
+	spans []*RecordedSpan
+}
+
+// NewRecordingTracer new in-memory Tracer.
+func NewRecordingTracer() *RecordingTracer {
+	return &RecordingTracer{}
+}
+
+type recordingSpanKey struct{}
+
+// Start See: Tracer.Start
+func (t *RecordingTracer) Start(ctx context.Context, name string) (context.Context, Span) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	s := &RecordedSpan{
+		ID:         len(t.spans) + 1,
+		Name:       name,
+		Attributes: map[string]any{},
+		Start:      time.Now(),
+	}
+	if parent, ok := ctx.Value(recordingSpanKey{}).(*RecordedSpan); ok {
+		s.ParentID = parent.ID
+	}
+	t.spans = append(t.spans, s)
+	return context.WithValue(ctx, recordingSpanKey{}, s), &recordingSpan{tracer: t, span: s}
+}
+
+// Spans Returns the recorded spans, in the order they were started.
+func (t *RecordingTracer) Spans() []RecordedSpan {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	spans := make([]RecordedSpan, 0, len(t.spans))
+	for _, s := range t.spans {
+		span := *s
+		span.Attributes = make(map[string]any, len(s.Attributes))
+		for k, v := range s.Attributes {
+			span.Attributes[k] = v
+		}
+		span.Errors = append([]error(nil), s.Errors...)
+		spans = append(spans, span)
+	}
+	return spans
+}
+
+// Reset Discards the recorded spans.
+func (t *RecordingTracer) Reset() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.spans = nil
+}
+
+type recordingSpan struct {
+	tracer *RecordingTracer
+	span   *RecordedSpan
+}
+
+func (s *recordingSpan) SetAttributes(attrs ...Attribute) {
+	s.tracer.mu.Lock()
+	defer s.tracer.mu.Unlock()
+	for _, attr := range attrs {
+		s.span.Attributes[attr.Key] = attr.Value
+	}
+}
+
+func (s *recordingSpan) RecordError(err error) {
+	s.tracer.mu.Lock()
+	defer s.tracer.mu.Unlock()
+	s.span.Errors = append(s.span.Errors, err)
+}
+
+func (s *recordingSpan) End() {
+	s.tracer.mu.Lock()
+	defer s.tracer.mu.Unlock()
+	s.span.End = time.Now()
+	s.span.Ended = true
+}
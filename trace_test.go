@@ -0,0 +1,118 @@
+package stream
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestTrace(t *testing.T) {
+	tests := []struct {
+		name       string
+		input      []int
+		goroutines int
+		partitions int
+	}{
+		{
+			name:       "case",
+			input:      []int{1, 2, 3, 4, 5, 6},
+			goroutines: 0,
+			partitions: 0,
+		},
+		{
+			name:       "case",
+			input:      []int{1, 2, 3, 4, 5, 6},
+			goroutines: 3,
+			partitions: 3,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tracer := NewRecordingTracer()
+			got := NewSlice(tt.input).
+				Parallel(tt.goroutines).
+				Trace(tracer).
+				Filter(func(v int) bool { return v > 2 }).Label("gt2").
+				ToSlice()
+			assert.Equal(t, []int{3, 4, 5, 6}, got)
+
+			spans := tracer.Spans()
+			assert.Equal(t, 2+tt.partitions, len(spans))
+
+			evaluation := spans[0]
+			assert.Equal(t, "stream.evaluation", evaluation.Name)
+			assert.Equal(t, 0, evaluation.ParentID)
+			assert.Equal(t, 6, evaluation.Attributes["stream.elements"])
+
+			stage := spans[1]
+			assert.Equal(t, "stream.stage", stage.Name)
+			assert.Equal(t, evaluation.ID, stage.ParentID)
+			assert.Equal(t, "gt2", stage.Attributes["stream.stage.label"])
+			assert.Equal(t, int64(6), stage.Attributes["stream.stage.in"])
+			assert.Equal(t, int64(2), stage.Attributes["stream.stage.dropped"])
+
+			for _, s := range spans[2:] {
+				assert.Equal(t, "stream.partition", s.Name)
+				assert.Equal(t, evaluation.ID, s.ParentID)
+				assert.Equal(t, 2, s.Attributes["stream.partition.processed"])
+			}
+			for _, s := range spans {
+				assert.True(t, s.Ended)
+			}
+		})
+	}
+}
+
+func TestTracePanic(t *testing.T) {
+	tests := []struct {
+		name       string
+		goroutines int
+	}{
+		{
+			name:       "case",
+			goroutines: 0,
+		},
+		{
+			name:       "case",
+			goroutines: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tracer := NewRecordingTracer()
+			assert.PanicsWithValue(t, "boom", func() {
+				NewSlice([]int{1, 2, 3, 4}).
+					Parallel(tt.goroutines).
+					Trace(tracer).
+					Map(func(v int) int {
+						if v == 4 {
+							panic("boom")
+						}
+						return v
+					}).
+					ToSlice()
+			})
+
+			spans := tracer.Spans()
+			assert.Equal(t, "stream.evaluation", spans[0].Name)
+			assert.EqualError(t, spans[0].Errors[0], "panic: boom")
+			for _, s := range spans {
+				assert.True(t, s.Ended)
+				if s.Name == "stream.partition" && s.Attributes["stream.partition"] == 1 {
+					assert.EqualError(t, s.Errors[0], "panic: boom")
+				}
+			}
+		})
+	}
+}
+
+func TestNoopTracer(t *testing.T) {
+	ctx, span := startSpan(nil, nil, "noop")
+	assert.Equal(t, context.Background(), ctx)
+	span.SetAttributes(Attribute{Key: "k", Value: 1})
+	span.RecordError(nil)
+	span.End()
+
+	got := NewSlice([]int{1, 2}).Trace(NoopTracer{}).Map(func(v int) int { return v * 2 }).ToSlice()
+	assert.Equal(t, []int{2, 4}, got)
+}
@@ -0,0 +1,175 @@
+package stream
+
+// Pair Tuple of two values. See: Zip, Enumerate
+type Pair[A any, B any] struct {
+	First  A
+	Second B
+}
+
+// Triple Tuple of three values. See: Zip3
+type Triple[A any, B any, C any] struct {
+	First  A
+	Second B
+	Third  C
+}
+
+// Zip Returns a stream of the pairs of the elements of a and b at the same position, as long as the shorter stream.
+// The elements are pulled lazily from both streams, so they can be infinite.
+// The stream inherits the plan and the options of a.
+//
+// Support Parallel, for the stages after Zip.
+func Zip[A any, B any](a SliceStream[A], b SliceStream[B]) SliceStream[Pair[A, B]] {
+	info := StageInfo{Name: "Zip"}
+	ua, ub := zipInputOf(a.Pipeline, info), zipInputOf(b.Pipeline, info)
+	next := func() (p Pair[A, B], ok bool) {
+		if p.First, ok = ua.pull(); !ok {
+			return
+		}
+		p.Second, ok = ub.pull()
+		return
+	}
+	return SliceStream[Pair[A, B]]{Pipeline: pipelineZip(next, ua, ub)}
+}
+
+// Zip3 Returns a stream of the triples of the elements of a, b and c at the same position, as long as the shortest stream.
+// See: Zip
+//
+// Support Parallel, for the stages after Zip3.
+func Zip3[A any, B any, C any](a SliceStream[A], b SliceStream[B], c SliceStream[C]) SliceStream[Triple[A, B, C]] {
+	info := StageInfo{Name: "Zip3"}
+	ua, ub, uc := zipInputOf(a.Pipeline, info), zipInputOf(b.Pipeline, info), zipInputOf(c.Pipeline, info)
+	next := func() (t Triple[A, B, C], ok bool) {
+		if t.First, ok = ua.pull(); !ok {
+			return
+		}
+		if t.Second, ok = ub.pull(); !ok {
+			return
+		}
+		t.Third, ok = uc.pull()
+		return
+	}
+	return SliceStream[Triple[A, B, C]]{Pipeline: pipelineZip(next, ua, ub, uc)}
+}
+
+// Unzip Returns the first values and the second values of the pairs of the stream, in their order.
+func Unzip[A any, B any](stream SliceStream[Pair[A, B]]) ([]A, []B) {
+	stream.evaluation()
+	if stream.source == nil {
+		return nil, nil
+	}
+	as, bs := make([]A, len(stream.source)), make([]B, len(stream.source))
+	for i, p := range stream.source {
+		as[i], bs[i] = p.First, p.Second
+	}
+	return as, bs
+}
+
+// Enumerate Returns a stream of the pairs of the index and the element of this stream.
+// The index is the one passed to the stages, like the index of ForEach:
+// the position of the element in the source, or in the expanded stream after FlatMap.
+//
+// Support Parallel.
+func Enumerate[E any](stream SliceStream[E]) SliceStream[Pair[int, E]] {
+	pipe := pipelineMapIndex(stream.Pipeline, StageInfo{Name: "Enumerate"}, func(index int, e E) Pair[int, E] {
+		return Pair[int, E]{First: index, Second: e}
+	})
+	return SliceStream[Pair[int, E]]{Pipeline: pipe}
+}
+
+// ToMap Returns a map of the pairs of the stream, the first value of each pair is its key.
+// If several pairs have the same key then the last one is kept.
+func ToMap[K comparable, V any](stream SliceStream[Pair[K, V]]) map[K]V {
+	stream.evaluation()
+	if stream.source == nil {
+		return nil
+	}
+	m := make(map[K]V, len(stream.source))
+	for _, p := range stream.source {
+		m[p.First] = p.Second
+	}
+	return m
+}
+
+// zipInput Upstream pipeline of Zip, whatever the type of its elements.
+type zipInput interface {
+	// state Returns whether the upstream is empty, whether it is infinite, and the error of its previous evaluations.
+	state() (empty bool, infinite bool, err error)
+	// genErr Returns the error that stopped the source of the upstream, if any.
+	genErr() error
+	close()
+}
+
+// zipSource Upstream pipeline of Zip, the pending stages of the pipeline are evaluated as its elements are pulled.
+type zipSource[E any] struct {
+	up *Pipeline[E]
+}
+
+// zipInputOf Returns the upstream of Zip of the pipeline. See: pipelinePull
+func zipInputOf[E any](pipe *Pipeline[E], info StageInfo) *zipSource[E] {
+	up := &Pipeline[E]{}
+	pipelinePull(pipe, up, info)
+	return &zipSource[E]{up: up}
+}
+
+// pull Pulls the next element of the upstream pipeline.
+func (s *zipSource[E]) pull() (e E, ok bool) {
+	return s.up.gen.next()
+}
+
+func (s *zipSource[E]) state() (empty bool, infinite bool, err error) {
+	return s.up.gen == nil, s.up.gen != nil && s.up.gen.infinite, s.up.err
+}
+
+func (s *zipSource[E]) genErr() error {
+	if s.up.gen == nil {
+		return nil
+	}
+	return s.up.gen.err
+}
+
+func (s *zipSource[E]) close() {
+	if s.up.gen != nil && s.up.gen.close != nil {
+		s.up.gen.close()
+	}
+}
+
+// pipelineZip Returns a pipeline of the tuples pulled by next from the inputs,
+// inheriting the plan and the options of the first input.
+// If an input is empty then the pipeline is empty, it is infinite if all the inputs are infinite.
+func pipelineZip[R any, E any](next func() (R, bool), first *zipSource[E], others ...zipInput) *Pipeline[R] {
+	dst := &Pipeline[R]{}
+	inherit(dst, first.up)
+	dst.pending = first.up.pending
+	inputs := append([]zipInput{first}, others...)
+	empty, infinite := false, true
+	for _, in := range inputs {
+		e, inf, err := in.state()
+		empty, infinite = empty || e, infinite && inf
+		if dst.err == nil {
+			dst.err = err
+		}
+	}
+	closeAll := func() {
+		for _, in := range inputs {
+			in.close()
+		}
+	}
+	if empty {
+		closeAll()
+		return dst
+	}
+
+	gen := pulled[R](infinite, nil)
+	gen.next = func() (r R, ok bool) {
+		r, ok = next()
+		for _, in := range inputs {
+			if err := in.genErr(); err != nil && gen.err == nil {
+				gen.err = err
+			}
+		}
+		return
+	}
+	gen.close = closeAll
+	dst.gen = gen
+	return dst
+}
@@ -0,0 +1,77 @@
+package stream
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestZip(t *testing.T) {
+	names := NewSlice([]string{"a", "b", "c", "d"})
+	ages := NewSlice([]int{1, 2, 3})
+	got := Zip(names, ages).ToSlice()
+	assert.Equal(t, []Pair[string, int]{{"a", 1}, {"b", 2}, {"c", 3}}, got)
+
+	// The streams are pulled lazily, the stages of both are evaluated, and the stages after Zip support Parallel.
+	evens := Iterate(0, func(v int) int { return v + 1 }).Filter(func(v int) bool { return v%2 == 0 })
+	labels := FlatMap(Range(0, 1000, 1).SliceStream, func(v int) []string { return []string{strconv.Itoa(v)} })
+	sums := Zip(evens, labels).
+		Parallel(4).
+		Map(func(p Pair[int, string]) Pair[int, string] { return Pair[int, string]{p.First * 10, p.Second} }).
+		ToSlice()
+	assert.Len(t, sums, 1000)
+	assert.Equal(t, Pair[int, string]{19980, "999"}, sums[999])
+
+	infinite := Zip(Iterate(1, func(v int) int { return v * 2 }), Iterate("", func(s string) string { return s + "x" }))
+	assert.Equal(t, []Pair[int, string]{{1, ""}, {2, "x"}, {4, "xx"}}, infinite.Limit(3).ToSlice())
+	assert.Panics(t, func() {
+		Zip(Iterate(1, func(v int) int { return v }), Iterate(1, func(v int) int { return v })).ToSlice()
+	})
+
+	assert.Nil(t, Zip(NewSlice[int](nil), ages).ToSlice())
+	assert.Empty(t, Zip(NewSlice([]int{}), ages).ToSlice())
+	assert.Contains(t, Zip(names, ages).Explain(), "Zip")
+
+	// An error of a source stops the zipped stream.
+	errRead := errors.New("read")
+	lines := FromReader(&failingReader{r: strings.NewReader("a\nb\n"), err: errRead}, nil)
+	zipped, err := Zip(Range(0, 10, 1).SliceStream, lines).ToSliceErr()
+	assert.Equal(t, []Pair[int, string]{{0, "a"}, {1, "b"}}, zipped)
+	assert.ErrorIs(t, err, errRead)
+}
+
+func TestZip3(t *testing.T) {
+	got := Zip3(NewSlice([]int{1, 2, 3}), NewSlice([]string{"a", "b"}), NewSlice([]bool{true, false, true})).ToSlice()
+	assert.Equal(t, []Triple[int, string, bool]{{1, "a", true}, {2, "b", false}}, got)
+	assert.Nil(t, Zip3(NewSlice([]int{1}), NewSlice([]int{1}), NewSlice[int](nil)).ToSlice())
+}
+
+func TestUnzip(t *testing.T) {
+	keys, values := Unzip(Zip(NewSlice([]string{"a", "b"}), NewSlice([]int{1, 2})))
+	assert.Equal(t, []string{"a", "b"}, keys)
+	assert.Equal(t, []int{1, 2}, values)
+
+	keys, values = Unzip(NewSlice[Pair[string, int]](nil))
+	assert.Nil(t, keys)
+	assert.Nil(t, values)
+}
+
+func TestEnumerate(t *testing.T) {
+	for _, goroutines := range []int{0, 3} {
+		got := Enumerate(NewSlice([]string{"a", "b", "c", "d"}).Parallel(goroutines).Filter(func(s string) bool { return s != "b" })).ToSlice()
+		assert.Equal(t, []Pair[int, string]{{0, "a"}, {2, "c"}, {3, "d"}}, got)
+	}
+	got := Enumerate(NewSlice([]string{"ab", "c"}).FlatMap(func(s string) []string { return strings.Split(s, "") })).ToSlice()
+	assert.Equal(t, []Pair[int, string]{{0, "a"}, {1, "b"}, {2, "c"}}, got)
+	assert.Nil(t, Enumerate(NewSlice[int](nil)).ToSlice())
+}
+
+func TestToMap(t *testing.T) {
+	m := ToMap(Zip(NewSlice([]string{"a", "b", "a"}), NewSlice([]int{1, 2, 3})))
+	assert.Equal(t, map[string]int{"a": 3, "b": 2}, m)
+	assert.Equal(t, map[int]string{0: "x", 1: "y"}, ToMap(Enumerate(NewSlice([]string{"x", "y"}))))
+	assert.Nil(t, ToMap(NewSlice[Pair[string, int]](nil)))
+}
@@ -0,0 +1,305 @@
+package stream
+
+import (
+	"sort"
+	"time"
+)
+
+// Window Elements of a stream whose timestamps are within the interval from Start (inclusive) to End (exclusive).
+type Window[E any] struct {
+	Start time.Time
+	End   time.Time
+	// Elements The elements of the window, in the order they were received.
+	Elements []E
+}
+
+// Clock Tells the current time, so the time windows can be tested without waiting.
+type Clock interface {
+	Now() time.Time
+}
+
+// ClockFunc Adapts a func to a Clock.
+type ClockFunc func() time.Time
+
+// Now Returns f().
+func (f ClockFunc) Now() time.Time {
+	return f()
+}
+
+// WindowOption Configures WindowByTime and SessionWindow over the elements of type E.
+type WindowOption[E any] func(*windowOptions[E])
+
+type windowOptions[E any] struct {
+	lateness time.Duration
+	late     func(e E)
+	clock    Clock
+}
+
+func newWindowOptions[E any](opts []WindowOption[E]) windowOptions[E] {
+	o := windowOptions[E]{clock: ClockFunc(time.Now)}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// WithAllowedLateness Sets how late an element can be received, 0 by default.
+// The watermark lags the greatest timestamp received by the allowed lateness,
+// a window is emitted once the watermark reaches its end, so elements out of order by up to the allowed lateness are kept.
+func WithAllowedLateness[E any](lateness time.Duration) WindowOption[E] {
+	return func(o *windowOptions[E]) {
+		if lateness > 0 {
+			o.lateness = lateness
+		}
+	}
+}
+
+// WithLateHandler Sets the handler of the late elements, received after their windows were emitted.
+// The late elements are dropped, the handler is called with each of them.
+func WithLateHandler[E any](handler func(E)) WindowOption[E] {
+	return func(o *windowOptions[E]) {
+		o.late = handler
+	}
+}
+
+// WithClock Sets the clock timestamping the elements when they are received if the timestamp func is nil, time.Now by default.
+// The clock is only read when an element is received, it does not fire the windows while the source is idle.
+func WithClock[E any](clock Clock) WindowOption[E] {
+	return func(o *windowOptions[E]) {
+		o.clock = clock
+	}
+}
+
+// WindowByTime Returns a stream of the windows of size of the elements of the stream, by the timestamps returned by ts.
+// The windows start every slide, aligned on multiples of slide since the zero time,
+// a slide equal to size gives tumbling windows, a smaller slide gives sliding windows sharing elements.
+// If slide <= 0 then it is size. If ts is nil then the elements are timestamped by the clock when they are received.
+//
+// The windows are emitted in the order of their start, as soon as the watermark reaches their end, See: WithAllowedLateness.
+// The windows still open at the end of the input are emitted then, so WindowByTime works on sources read lazily such as FromChan,
+// and on infinite sources with a following short-circuiting operation.
+// The watermark only advances when an element is received, so while a source such as a channel is idle,
+// its open windows are held until the next element or the end of the input.
+// Windows without elements are not emitted.
+//
+// The elements are received in their order, the options of the stream set before WindowByTime apply to the stages before it.
+func WindowByTime[E any](stream SliceStream[E], ts func(E) time.Time, size, slide time.Duration, opts ...WindowOption[E]) SliceStream[Window[E]] {
+	if size <= 0 {
+		panic("stream: WindowByTime size must be positive")
+	}
+	if slide <= 0 {
+		slide = size
+	}
+	dst := pipelineWindow[E](stream.Pipeline, StageInfo{Name: "WindowByTime", Stateful: true}, ts, newWindowOptions(opts),
+		&slidingWindows[E]{size: size, slide: slide, open: map[int64]*openWindow[E]{}})
+	return SliceStream[Window[E]]{Pipeline: dst}
+}
+
+// SessionWindow Returns a stream of the sessions of the elements of the stream, by the timestamps returned by ts.
+// A session groups the elements less than gap apart, it ends gap after its last element.
+// If ts is nil then the elements are timestamped by the clock when they are received.
+//
+// The sessions are emitted like the windows of WindowByTime, a late element can merge sessions that are not emitted yet.
+func SessionWindow[E any](stream SliceStream[E], ts func(E) time.Time, gap time.Duration, opts ...WindowOption[E]) SliceStream[Window[E]] {
+	if gap <= 0 {
+		panic("stream: SessionWindow gap must be positive")
+	}
+	dst := pipelineWindow[E](stream.Pipeline, StageInfo{Name: "SessionWindow", Stateful: true}, ts, newWindowOptions(opts),
+		&sessionWindows[E]{gap: gap})
+	return SliceStream[Window[E]]{Pipeline: dst}
+}
+
+// pipelineWindow Returns a new pipeline of the windows assigned to the elements of the pipeline,
+// its source is a generator pulling the elements as the windows are pulled.
+func pipelineWindow[E any](pipe *Pipeline[E], info StageInfo, ts func(E) time.Time, o windowOptions[E], assigner windowAssigner[E]) *Pipeline[Window[E]] {
+	up := &Pipeline[E]{}
+	pipelinePull(pipe, up, info)
+	dst := &Pipeline[Window[E]]{}
+	inherit(dst, up)
+	dst.pending = up.pending
+	if up.gen == nil {
+		return dst
+	}
+
+	src := up.gen
+	w := &windowing[E]{src: src, ts: ts, o: o, assigner: assigner}
+	dst.gen = pulled(src.infinite, w.next)
+	dst.gen.close = src.close
+	w.err = &dst.gen.err
+	return dst
+}
+
+// windowAssigner Holds the open windows of the elements.
+type windowAssigner[E any] interface {
+	// add Adds the element received seq-th to its open windows, returns false if all its windows are closed.
+	add(seq int, t time.Time, e E, watermark time.Time) bool
+	// fire Closes the windows ending before the watermark, or all the windows if all, in the order of their start.
+	fire(watermark time.Time, all bool) []Window[E]
+}
+
+// windowing Pulls the elements of a generator and the windows they complete.
+type windowing[E any] struct {
+	src       *generator[E]
+	ts        func(E) time.Time
+	o         windowOptions[E]
+	assigner  windowAssigner[E]
+	watermark time.Time
+	seq       int
+	ready     []Window[E]
+	done      bool
+	err       *error
+}
+
+func (w *windowing[E]) next() (win Window[E], ok bool) {
+	for len(w.ready) == 0 {
+		if w.done {
+			return
+		}
+		e, ok := w.src.next()
+		if !ok {
+			if w.src.err != nil {
+				*w.err = w.src.err
+			}
+			w.done = true
+			w.ready = w.assigner.fire(w.watermark, true)
+			continue
+		}
+
+		var t time.Time
+		if w.ts != nil {
+			t = w.ts(e)
+		} else {
+			t = w.o.clock.Now()
+		}
+		if mark := t.Add(-w.o.lateness); mark.After(w.watermark) {
+			w.watermark = mark
+		}
+		if !w.assigner.add(w.seq, t, e, w.watermark) && w.o.late != nil {
+			w.o.late(e)
+		}
+		w.seq++
+		w.ready = w.assigner.fire(w.watermark, false)
+	}
+	win = w.ready[0]
+	w.ready = w.ready[1:]
+	return win, true
+}
+
+// sequenced Element with its order of reception.
+type sequenced[E any] struct {
+	seq  int
+	elem E
+}
+
+// openWindow Window not emitted yet.
+type openWindow[E any] struct {
+	start, end time.Time
+	elems      []sequenced[E]
+}
+
+func (ow *openWindow[E]) window() Window[E] {
+	elems := make([]E, len(ow.elems))
+	for i, s := range ow.elems {
+		elems[i] = s.elem
+	}
+	return Window[E]{Start: ow.start, End: ow.end, Elements: elems}
+}
+
+// slidingWindows The windows of size starting every slide, keyed by the nanoseconds of their start.
+type slidingWindows[E any] struct {
+	size, slide time.Duration
+	open        map[int64]*openWindow[E]
+}
+
+func (sw *slidingWindows[E]) add(seq int, t time.Time, e E, watermark time.Time) bool {
+	assigned, closed := false, false
+	for start := t.Truncate(sw.slide); start.Add(sw.size).After(t); start = start.Add(-sw.slide) {
+		end := start.Add(sw.size)
+		if !end.After(watermark) {
+			closed = true
+			continue
+		}
+		key := start.UnixNano()
+		ow, ok := sw.open[key]
+		if !ok {
+			ow = &openWindow[E]{start: start, end: end}
+			sw.open[key] = ow
+		}
+		ow.elems = append(ow.elems, sequenced[E]{seq: seq, elem: e})
+		assigned = true
+	}
+	return assigned || !closed
+}
+
+func (sw *slidingWindows[E]) fire(watermark time.Time, all bool) []Window[E] {
+	var fired []*openWindow[E]
+	for key, ow := range sw.open {
+		if all || !ow.end.After(watermark) {
+			fired = append(fired, ow)
+			delete(sw.open, key)
+		}
+	}
+	sort.Slice(fired, func(i, j int) bool { return fired[i].start.Before(fired[j].start) })
+	windows := make([]Window[E], len(fired))
+	for i, ow := range fired {
+		windows[i] = ow.window()
+	}
+	return windows
+}
+
+// sessionWindows The open sessions, ordered by their start and not overlapping.
+type sessionWindows[E any] struct {
+	gap      time.Duration
+	sessions []*openWindow[E]
+}
+
+func (sw *sessionWindows[E]) add(seq int, t time.Time, e E, watermark time.Time) bool {
+	merged := &openWindow[E]{start: t, end: t.Add(sw.gap), elems: []sequenced[E]{{seq: seq, elem: e}}}
+	if !merged.end.After(watermark) {
+		return false
+	}
+
+	// The sessions overlapping the session of the element are merged into it.
+	kept := sw.sessions[:0]
+	at := -1
+	for _, s := range sw.sessions {
+		if s.start.Before(merged.end) && merged.start.Before(s.end) {
+			if s.start.Before(merged.start) {
+				merged.start = s.start
+			}
+			if s.end.After(merged.end) {
+				merged.end = s.end
+			}
+			merged.elems = append(merged.elems, s.elems...)
+			continue
+		}
+		if at < 0 && merged.start.Before(s.start) {
+			at = len(kept)
+		}
+		kept = append(kept, s)
+	}
+	sort.Slice(merged.elems, func(i, j int) bool { return merged.elems[i].seq < merged.elems[j].seq })
+
+	if at < 0 {
+		at = len(kept)
+	}
+	kept = append(kept, nil)
+	copy(kept[at+1:], kept[at:])
+	kept[at] = merged
+	sw.sessions = kept
+	return true
+}
+
+func (sw *sessionWindows[E]) fire(watermark time.Time, all bool) []Window[E] {
+	var windows []Window[E]
+	kept := sw.sessions[:0]
+	for _, s := range sw.sessions {
+		if all || !s.end.After(watermark) {
+			windows = append(windows, s.window())
+			continue
+		}
+		kept = append(kept, s)
+	}
+	sw.sessions = kept
+	return windows
+}
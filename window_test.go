@@ -0,0 +1,163 @@
+package stream
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type event struct {
+	at    int // seconds
+	value int
+}
+
+var epoch = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func eventTime(e event) time.Time {
+	return epoch.Add(time.Duration(e.at) * time.Second)
+}
+
+// windowValues Returns the start second of each window with its values.
+func windowValues(windows []Window[event]) map[int][]int {
+	got := map[int][]int{}
+	for _, w := range windows {
+		start := int(w.Start.Sub(epoch) / time.Second)
+		for _, e := range w.Elements {
+			got[start] = append(got[start], e.value)
+		}
+	}
+	return got
+}
+
+func windowStarts(windows []Window[event]) []int {
+	starts := make([]int, 0, len(windows))
+	for _, w := range windows {
+		starts = append(starts, int(w.Start.Sub(epoch)/time.Second))
+	}
+	return starts
+}
+
+func TestWindowByTimeTumbling(t *testing.T) {
+	events := []event{{1, 1}, {3, 2}, {10, 3}, {12, 4}, {31, 5}}
+	got := WindowByTime(NewSlice(events), eventTime, 10*time.Second, 0).ToSlice()
+	assert.Equal(t, []int{0, 10, 30}, windowStarts(got))
+	assert.Equal(t, map[int][]int{0: {1, 2}, 10: {3, 4}, 30: {5}}, windowValues(got))
+	assert.Equal(t, epoch.Add(10*time.Second), got[0].End)
+
+	empty := WindowByTime(NewSlice([]event{}), eventTime, time.Second, 0).ToSlice()
+	assert.Empty(t, empty)
+	assert.Empty(t, WindowByTime(NewSlice[event](nil), eventTime, time.Second, 0).ToSlice())
+
+	assert.Panics(t, func() { WindowByTime(NewSlice(events), eventTime, 0, 0) })
+	assert.Panics(t, func() { SessionWindow(NewSlice(events), eventTime, 0) })
+}
+
+func TestWindowByTimeSliding(t *testing.T) {
+	events := []event{{1, 1}, {6, 2}, {12, 3}}
+	got := WindowByTime(NewSlice(events), eventTime, 10*time.Second, 5*time.Second).ToSlice()
+	assert.Equal(t, []int{-5, 0, 5, 10}, windowStarts(got))
+	assert.Equal(t, map[int][]int{-5: {1}, 0: {1, 2}, 5: {2, 3}, 10: {3}}, windowValues(got))
+
+	// Elements between the windows are skipped.
+	got = WindowByTime(NewSlice(events), eventTime, 5*time.Second, 10*time.Second).ToSlice()
+	assert.Equal(t, map[int][]int{0: {1}, 10: {3}}, windowValues(got))
+}
+
+func TestWindowByTimeLateness(t *testing.T) {
+	events := []event{{1, 1}, {11, 2}, {4, 3}, {22, 4}, {9, 5}, {14, 6}}
+
+	var late []int
+	onLate := WithLateHandler(func(e event) { late = append(late, e.value) })
+	got := WindowByTime(NewSlice(events), eventTime, 10*time.Second, 0, onLate).ToSlice()
+	assert.Equal(t, map[int][]int{0: {1}, 10: {2}, 20: {4}}, windowValues(got))
+	assert.Equal(t, []int{3, 5, 6}, late)
+
+	late = nil
+	got = WindowByTime(NewSlice(events), eventTime, 10*time.Second, 0, onLate, WithAllowedLateness[event](5*time.Second)).ToSlice()
+	assert.Equal(t, map[int][]int{0: {1, 3}, 10: {2, 6}, 20: {4}}, windowValues(got))
+	assert.Equal(t, []int{5}, late)
+}
+
+func TestWindowByTimeLazy(t *testing.T) {
+	ch := make(chan event)
+	windows := make(chan Window[event])
+	go WindowByTime(FromChan(ch), eventTime, 10*time.Second, 0).ToChan(windows)
+
+	ch <- event{1, 1}
+	ch <- event{2, 2}
+	ch <- event{10, 3}
+	// The first window is emitted once the watermark reaches its end, before the input ends.
+	w := <-windows
+	assert.Equal(t, epoch, w.Start)
+	assert.Equal(t, []event{{1, 1}, {2, 2}}, w.Elements)
+
+	ch <- event{15, 4}
+	close(ch)
+	w = <-windows
+	assert.Equal(t, []event{{10, 3}, {15, 4}}, w.Elements)
+	_, ok := <-windows
+	assert.False(t, ok)
+
+	// Infinite sources with a short-circuiting operation.
+	got := WindowByTime(RangeFrom(0, 1).SliceStream, func(v int) time.Time { return epoch.Add(time.Duration(v) * time.Second) },
+		3*time.Second, 0).Limit(2).ToSlice()
+	assert.Len(t, got, 2)
+	assert.Equal(t, []int{3, 4, 5}, got[1].Elements)
+
+	// Upstream stages and errors.
+	errRead := errors.New("read")
+	stream := WindowByTime(NewSlice([]event{{1, 1}, {2, 2}, {3, 3}, {12, 4}}).Filter(func(e event) bool { return e.value != 2 }),
+		eventTime, 10*time.Second, 0)
+	assert.Equal(t, map[int][]int{0: {1, 3}, 10: {4}}, windowValues(stream.ToSlice()))
+	assert.NoError(t, stream.Err())
+
+	reader := FromReader(&failingReader{r: strings.NewReader("a\nb\n"), err: errRead}, nil)
+	stream2 := WindowByTime(reader, nil, time.Hour, 0)
+	_ = stream2.ToSlice()
+	assert.ErrorIs(t, stream2.Err(), errRead)
+}
+
+func TestWindowClock(t *testing.T) {
+	now := epoch
+	clock := ClockFunc(func() time.Time {
+		now = now.Add(4 * time.Second)
+		return now
+	})
+	got := WindowByTime(NewSlice([]event{{0, 1}, {0, 2}, {0, 3}, {0, 4}}), nil, 10*time.Second, 0, WithClock[event](clock)).ToSlice()
+	assert.Equal(t, []int{0, 10}, windowStarts(got))
+	assert.Equal(t, map[int][]int{0: {1, 2}, 10: {3, 4}}, windowValues(got))
+}
+
+func TestSessionWindow(t *testing.T) {
+	events := []event{{1, 1}, {3, 2}, {10, 3}, {11, 4}, {30, 5}}
+	got := SessionWindow(NewSlice(events), eventTime, 5*time.Second).ToSlice()
+	assert.Equal(t, []int{1, 10, 30}, windowStarts(got))
+	assert.Equal(t, map[int][]int{1: {1, 2}, 10: {3, 4}, 30: {5}}, windowValues(got))
+	assert.Equal(t, epoch.Add(8*time.Second), got[0].End)
+	assert.Equal(t, epoch.Add(16*time.Second), got[1].End)
+
+	// A late element merges two sessions, its elements are kept in the order they were received.
+	events = []event{{1, 1}, {9, 2}, {5, 3}, {30, 4}, {2, 5}}
+	var late []int
+	got = SessionWindow(NewSlice(events), eventTime, 5*time.Second,
+		WithAllowedLateness[event](10*time.Second), WithLateHandler(func(e event) { late = append(late, e.value) })).ToSlice()
+	assert.Equal(t, []int{1, 30}, windowStarts(got))
+	assert.Equal(t, map[int][]int{1: {1, 2, 3}, 30: {4}}, windowValues(got))
+	assert.Equal(t, epoch.Add(14*time.Second), got[0].End)
+	assert.Equal(t, []int{5}, late)
+
+	// Parallel stages after the windows.
+	sessions := SessionWindow(NewSlice([]event{{1, 1}, {2, 2}, {20, 3}}), eventTime, 5*time.Second).
+		Parallel(2).
+		Filter(func(w Window[event]) bool { return len(w.Elements) > 1 }).
+		ToSlice()
+	assert.Equal(t, []int{1}, windowStarts(sessions))
+}
+
+func TestWindowExplain(t *testing.T) {
+	plan := WindowByTime(NewSlice([]event{{1, 1}}), eventTime, time.Second, 0).Explain()
+	assert.Contains(t, plan, "WindowByTime [stateful]")
+}